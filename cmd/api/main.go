@@ -9,6 +9,9 @@ import (
 	"syscall"
 	"time"
 
+	"sync_drive_backend/internal/infrastructure/persistence/mongodb"
+	"sync_drive_backend/internal/infrastructure/persistence/mongodb/record"
+
 	"go.uber.org/zap"
 )
 
@@ -41,6 +44,11 @@ func main() {
 		app.Logger.Info("MongoDB connection successful")
 	}
 
+	// 同步 MongoDB 集合與索引（只建立缺少的項目，多餘索引僅回報）
+	if app.Config.GetBool("mongodb.syncSchema") {
+		syncMongoSchema(app)
+	}
+
 	// 檢查 Redis
 	if err := app.Redis.Ping(pingCtx).Err(); err != nil {
 		app.Logger.Error("Redis connection failed", zap.Error(err))
@@ -48,6 +56,42 @@ func main() {
 		app.Logger.Info("Redis connection successful")
 	}
 
+	// 檢查 MQTT
+	if app.MQTT.IsConnected() {
+		app.Logger.Info("MQTT connection successful")
+	} else {
+		app.Logger.Warn("MQTT not connected yet, retrying in background")
+	}
+
+	// 啟動兩級快取的跨副本失效訂閱
+	app.TwoLevel.Start()
+
+	// 啟動 Outbox Relay
+	if app.Config.GetBool("outbox.enabled") {
+		app.OutboxRelay.Start()
+		app.Logger.Info("Outbox relay started")
+	}
+
+	// 啟動 MongoDB Change Stream 訂閱（需要 Replica Set）
+	if app.Config.GetBool("changeStream.enabled") {
+		app.ChangeStream.Start()
+		app.Logger.Info("Change stream subscriber started")
+	}
+
+	// 啟動背景工作佇列
+	if app.Config.GetBool("queue.enabled") {
+		if err := app.JobQueue.Start(context.Background()); err != nil {
+			app.Logger.Fatal("Failed to start job queue", zap.Error(err))
+		}
+		app.Logger.Info("Job queue started")
+	}
+
+	// 啟動排程器（排程工作需在此之前註冊）
+	if app.Config.GetBool("scheduler.enabled") {
+		app.Scheduler.Start()
+		app.Logger.Info("Scheduler started")
+	}
+
 	// 記錄啟動資訊
 	app.Logger.Info("Starting SyncDrive API Server",
 		zap.String("env", app.Config.GetString("app.env")),
@@ -90,8 +134,55 @@ func main() {
 		app.Logger.Error("Server forced to shutdown", zap.Error(err))
 	}
 
+	// 停止背景工作
+	if err := app.OutboxRelay.Stop(ctx); err != nil {
+		app.Logger.Error("Outbox relay forced to stop", zap.Error(err))
+	}
+	if err := app.ChangeStream.Stop(ctx); err != nil {
+		app.Logger.Error("Change stream subscriber forced to stop", zap.Error(err))
+	}
+	if err := app.Scheduler.Stop(ctx); err != nil {
+		app.Logger.Error("Scheduler forced to stop", zap.Error(err))
+	}
+	if err := app.JobQueue.Stop(ctx); err != nil {
+		app.Logger.Error("Job queue forced to stop", zap.Error(err))
+	}
+	if err := app.TwoLevel.Stop(ctx); err != nil {
+		app.Logger.Error("Cache invalidation subscriber forced to stop", zap.Error(err))
+	}
+
+	// 關閉 MQTT 連接
+	app.MQTT.Disconnect(250)
+
 	// 同步日誌緩衝區
 	_ = app.Logger.Sync()
 
 	app.Logger.Info("Server exited")
 }
+
+// syncMongoSchema 啟動時同步 MongoDB 集合與索引
+func syncMongoSchema(app *App) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	report, err := mongodb.SyncSchema(ctx, app.MongoDB, record.Collections(), mongodb.SyncOptions{})
+	if err != nil {
+		app.Logger.Error("MongoDB schema sync failed", zap.Error(err))
+		return
+	}
+
+	if report.HasDrift() {
+		app.Logger.Info("MongoDB schema synced",
+			zap.Strings("created_collections", report.CreatedCollections),
+			zap.Strings("created_indexes", report.CreatedIndexes),
+			zap.Strings("updated_validators", report.UpdatedValidators),
+		)
+	}
+	if len(report.ChangedIndexes) > 0 || len(report.StaleIndexes) > 0 || len(report.Warnings) > 0 {
+		app.Logger.Warn("MongoDB schema drift detected, run cmd/mongoschema to reconcile",
+			zap.Strings("changed_indexes", report.ChangedIndexes),
+			zap.Strings("stale_indexes", report.StaleIndexes),
+			zap.Strings("warnings", report.Warnings),
+		)
+	}
+}
@@ -4,13 +4,37 @@
 package main
 
 import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
 	"sync_drive_backend/configs"
+	"sync_drive_backend/internal/common/consts"
+	"sync_drive_backend/internal/common/middleware/request"
+	"sync_drive_backend/internal/common/ratelimit"
+	authapp "sync_drive_backend/internal/core/auth/application"
+	authentity "sync_drive_backend/internal/core/auth/domain/entity"
+	authrepo "sync_drive_backend/internal/core/auth/domain/repository"
+	authservice "sync_drive_backend/internal/core/auth/domain/service"
+	authhttp "sync_drive_backend/internal/core/auth/interface/http"
+	"sync_drive_backend/internal/infrastructure/broker"
+	"sync_drive_backend/internal/infrastructure/event"
+	"sync_drive_backend/internal/infrastructure/mail"
+	"sync_drive_backend/internal/infrastructure/oidc"
 	"sync_drive_backend/internal/infrastructure/persistence/mongodb"
+	mongorepo "sync_drive_backend/internal/infrastructure/persistence/mongodb/repository"
 	"sync_drive_backend/internal/infrastructure/persistence/mysql"
+	mysqlrepo "sync_drive_backend/internal/infrastructure/persistence/mysql/repository"
 	redisinfra "sync_drive_backend/internal/infrastructure/persistence/redis"
+	"sync_drive_backend/internal/infrastructure/queue"
+	"sync_drive_backend/internal/infrastructure/scheduler"
 	"sync_drive_backend/internal/infrastructure/webserver"
+	"sync_drive_backend/pkg/jwt"
 	"sync_drive_backend/pkg/logger"
 
+	mqtt "github.com/eclipse/paho.mqtt.golang"
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 	redisclient "github.com/redis/go-redis/v9"
@@ -75,32 +99,541 @@ func ProvideMongoDB(cfg *viper.Viper) (*mongo.Database, error) {
 	return mongodb.Init(mongoCfg)
 }
 
-// ProvideRedis 提供 Redis 連接
-func ProvideRedis(cfg *viper.Viper) (*redisclient.Client, error) {
+// ProvideCacheKeys 提供根快取 key 產生器（{app}:{env}）
+func ProvideCacheKeys(cfg *viper.Viper) *redisinfra.KeyBuilder {
+	return redisinfra.NewKeyBuilder(cfg.GetString("app.name"), cfg.GetString("app.env"))
+}
+
+// ProvideCache 提供 Redis 快取
+func ProvideCache(cfg *viper.Viper, client redisclient.UniversalClient, keys *redisinfra.KeyBuilder) *redisinfra.Cache {
+	cacheCfg := &redisinfra.CacheConfig{
+		NegativeTTL: time.Duration(cfg.GetInt("cache.negativeTTLSeconds")) * time.Second,
+		JitterRatio: cfg.GetFloat64("cache.jitterRatio"),
+		Keys:        keys,
+	}
+
+	return redisinfra.NewCacheWithConfig(client, cacheCfg)
+}
+
+// ProvideTwoLevelCache 提供兩級快取（本地 LRU + Redis）
+func ProvideTwoLevelCache(cfg *viper.Viper, cache *redisinfra.Cache, client redisclient.UniversalClient, keys *redisinfra.KeyBuilder) *redisinfra.TwoLevelCache {
+	twoLevelCfg := &redisinfra.TwoLevelConfig{
+		LocalSize: cfg.GetInt("cache.localSize"),
+		LocalTTL:  time.Duration(cfg.GetInt("cache.localTTLSeconds")) * time.Second,
+		Channel:   keys.Key(cfg.GetString("cache.invalidationChannel")),
+	}
+
+	return redisinfra.NewTwoLevelCache(cache, client, twoLevelCfg)
+}
+
+// ProvideLocker 提供 Redis 分散式鎖
+func ProvideLocker(cfg *viper.Viper, client redisclient.UniversalClient, keys *redisinfra.KeyBuilder) *redisinfra.Locker {
+	lockerCfg := &redisinfra.LockerConfig{
+		RetryInterval: time.Duration(cfg.GetInt("lock.retryIntervalMs")) * time.Millisecond,
+		Keys:          keys,
+	}
+
+	return redisinfra.NewLocker(client, lockerCfg)
+}
+
+// ProvideChangeStreamSubscriber 提供 MongoDB Change Stream 訂閱器
+func ProvideChangeStreamSubscriber(cfg *viper.Viper, db *mongo.Database, redis redisclient.UniversalClient) (*mongodb.ChangeStreamSubscriber, error) {
+	var store mongodb.ResumeTokenStore
+	switch cfg.GetString("changeStream.tokenStore") {
+	case "mongo":
+		store = mongodb.NewMongoTokenStore(db, cfg.GetString("changeStream.tokenCollection"))
+	case "redis":
+		store = mongodb.NewRedisTokenStore(redis, cfg.GetString("changeStream.tokenKeyPrefix"))
+	default:
+		return nil, fmt.Errorf("unsupported change stream token store: %q", cfg.GetString("changeStream.tokenStore"))
+	}
+
+	streamCfg := &mongodb.ChangeStreamConfig{
+		MinBackoff:     time.Duration(cfg.GetInt("changeStream.minBackoffMs")) * time.Millisecond,
+		MaxBackoff:     time.Duration(cfg.GetInt("changeStream.maxBackoffSeconds")) * time.Second,
+		HandlerRetries: cfg.GetInt("changeStream.handlerRetries"),
+		TokenSaveEvery: cfg.GetInt("changeStream.tokenSaveEvery"),
+		StoreOpTimeout: time.Duration(cfg.GetInt("mongodb.timeout")) * time.Second,
+		MaxAwaitTime:   time.Duration(cfg.GetInt("changeStream.maxAwaitSeconds")) * time.Second,
+	}
+
+	return mongodb.NewChangeStreamSubscriber(db, store, streamCfg), nil
+}
+
+// ProvideRedis 提供 Redis 連接（依 redis.mode 選擇單機、Sentinel 或 Cluster）
+func ProvideRedis(cfg *viper.Viper) (redisclient.UniversalClient, error) {
 	redisCfg := &redisinfra.Config{
-		Host:     cfg.GetString("redis.host"),
-		Port:     cfg.GetInt("redis.port"),
-		Password: cfg.GetString("redis.password"),
-		DB:       cfg.GetInt("redis.db"),
-		PoolSize: cfg.GetInt("redis.poolSize"),
+		Mode:             cfg.GetString("redis.mode"),
+		Host:             cfg.GetString("redis.host"),
+		Port:             cfg.GetInt("redis.port"),
+		Addrs:            cfg.GetStringSlice("redis.addrs"),
+		MasterName:       cfg.GetString("redis.masterName"),
+		SentinelUsername: cfg.GetString("redis.sentinelUsername"),
+		SentinelPassword: cfg.GetString("redis.sentinelPassword"),
+		Username:         cfg.GetString("redis.username"),
+		Password:         cfg.GetString("redis.password"),
+		DB:               cfg.GetInt("redis.db"),
+		PoolSize:         cfg.GetInt("redis.poolSize"),
+		TLS:              cfg.GetBool("redis.tls"),
+		TLSCAFile:        cfg.GetString("redis.tlsCAFile"),
+		TLSServerName:    cfg.GetString("redis.tlsServerName"),
+		TLSSkipVerify:    cfg.GetBool("redis.tlsSkipVerify"),
+		DialTimeout:      time.Duration(cfg.GetInt("redis.dialTimeoutMs")) * time.Millisecond,
+		ReadTimeout:      time.Duration(cfg.GetInt("redis.readTimeoutMs")) * time.Millisecond,
+		WriteTimeout:     time.Duration(cfg.GetInt("redis.writeTimeoutMs")) * time.Millisecond,
 	}
 
 	return redisinfra.Init(redisCfg)
 }
 
+// ProvideMQTT 提供 MQTT 連接
+func ProvideMQTT(cfg *viper.Viper) (mqtt.Client, error) {
+	mqttCfg := &broker.Config{
+		Broker:   cfg.GetString("mqtt.broker"),
+		ClientID: cfg.GetString("mqtt.clientID"),
+		Username: cfg.GetString("mqtt.username"),
+		Password: cfg.GetString("mqtt.password"),
+		QoS:      byte(cfg.GetInt("mqtt.qos")),
+	}
+
+	return broker.Init(mqttCfg)
+}
+
+// ProvideEventPublisher 提供事件發佈者（依 outbox.publisher 選擇 MQTT 或 Redis）
+func ProvideEventPublisher(cfg *viper.Viper, mqttClient mqtt.Client, redis redisclient.UniversalClient) (event.Publisher, error) {
+	switch cfg.GetString("outbox.publisher") {
+	case "mqtt":
+		return broker.NewMQTTPublisher(mqttClient, byte(cfg.GetInt("mqtt.qos"))), nil
+	case "redis":
+		return broker.NewRedisPublisher(redis, cfg.GetInt64("outbox.streamMaxLen")), nil
+	default:
+		return nil, fmt.Errorf("unsupported outbox publisher: %q", cfg.GetString("outbox.publisher"))
+	}
+}
+
+// ProvideOutboxRelay 提供 Outbox Relay
+func ProvideOutboxRelay(cfg *viper.Viper, db *gorm.DB, publisher event.Publisher) *event.Relay {
+	relayCfg := &event.RelayConfig{
+		PollInterval:   time.Duration(cfg.GetInt("outbox.pollIntervalMs")) * time.Millisecond,
+		PublishTimeout: time.Duration(cfg.GetInt("outbox.publishTimeoutSeconds")) * time.Second,
+		BatchSize:      cfg.GetInt("outbox.batchSize"),
+		MaxAttempts:    cfg.GetInt("outbox.maxAttempts"),
+		RetryBackoff:   time.Duration(cfg.GetInt("outbox.retryBackoffSeconds")) * time.Second,
+		MaxBackoff:     time.Duration(cfg.GetInt("outbox.maxBackoffSeconds")) * time.Second,
+		Retention:      time.Duration(cfg.GetInt("outbox.retentionHours")) * time.Hour,
+		PruneInterval:  time.Duration(cfg.GetInt("outbox.pruneIntervalMinutes")) * time.Minute,
+	}
+
+	return event.NewRelay(db, publisher, relayCfg)
+}
+
+// ProvideJobQueue 提供背景工作佇列
+func ProvideJobQueue(cfg *viper.Viper, client redisclient.UniversalClient, keys *redisinfra.KeyBuilder) (*queue.Queue, error) {
+	var concurrency map[string]int
+	if err := cfg.UnmarshalKey("queue.concurrency", &concurrency); err != nil {
+		return nil, fmt.Errorf("failed to parse queue.concurrency: %w", err)
+	}
+
+	queueCfg := &queue.Config{
+		Concurrency:       concurrency,
+		Block:             time.Duration(cfg.GetInt("queue.blockMs")) * time.Millisecond,
+		VisibilityTimeout: time.Duration(cfg.GetInt("queue.visibilityTimeoutSeconds")) * time.Second,
+		ReclaimInterval:   time.Duration(cfg.GetInt("queue.reclaimIntervalSeconds")) * time.Second,
+		PromoteInterval:   time.Duration(cfg.GetInt("queue.promoteIntervalMs")) * time.Millisecond,
+		MaxAttempts:       cfg.GetInt("queue.maxAttempts"),
+		RetryBackoff:      time.Duration(cfg.GetInt("queue.retryBackoffSeconds")) * time.Second,
+		MaxBackoff:        time.Duration(cfg.GetInt("queue.maxBackoffSeconds")) * time.Second,
+		StreamMaxLen:      cfg.GetInt64("queue.streamMaxLen"),
+	}
+
+	return queue.New(client, keys, queueCfg), nil
+}
+
+// ProvideScheduler 提供分散式排程器
+func ProvideScheduler(cfg *viper.Viper, client redisclient.UniversalClient, keys *redisinfra.KeyBuilder, locker *redisinfra.Locker, db *mongo.Database) (*scheduler.Scheduler, error) {
+	location, err := time.LoadLocation(cfg.GetString("scheduler.timeZone"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid scheduler.timeZone: %w", err)
+	}
+
+	schedulerCfg := &scheduler.Config{
+		Location:       location,
+		LockTTL:        time.Duration(cfg.GetInt("scheduler.lockTTLSeconds")) * time.Second,
+		DefaultTimeout: time.Duration(cfg.GetInt("scheduler.defaultTimeoutMinutes")) * time.Minute,
+	}
+	history := mongorepo.NewSchedulerRunRepository(db, time.Duration(cfg.GetInt("mongodb.timeout"))*time.Second)
+
+	return scheduler.New(client, keys, locker, history, schedulerCfg), nil
+}
+
+// rateLimitPolicyConfig 限流策略配置（對應 rateLimit.default 與 rateLimit.rules）
+type rateLimitPolicyConfig struct {
+	Method        string `mapstructure:"method"`
+	Path          string `mapstructure:"path"`
+	Role          string `mapstructure:"role"`
+	Name          string `mapstructure:"name"`
+	Algorithm     string `mapstructure:"algorithm"`
+	Limit         int    `mapstructure:"limit"`
+	WindowSeconds int    `mapstructure:"windowSeconds"`
+	Burst         int    `mapstructure:"burst"`
+	KeyBy         string `mapstructure:"keyBy"`
+}
+
+// toPolicy 轉換為限流策略
+func (p *rateLimitPolicyConfig) toPolicy() ratelimit.Policy {
+	return ratelimit.Policy{
+		Name:      p.Name,
+		Algorithm: ratelimit.Algorithm(p.Algorithm),
+		Limit:     p.Limit,
+		Window:    time.Duration(p.WindowSeconds) * time.Second,
+		Burst:     p.Burst,
+		KeyBy:     ratelimit.KeyStrategy(p.KeyBy),
+	}
+}
+
+// ProvideRateLimiter 提供分散式限流中介層（Redis 不可用時降級為記憶體限流）
+func ProvideRateLimiter(cfg *viper.Viper, client redisclient.UniversalClient, keys *redisinfra.KeyBuilder) (*request.DistributedRateLimiter, error) {
+	var defaultPolicy rateLimitPolicyConfig
+	if err := cfg.UnmarshalKey("rateLimit.default", &defaultPolicy); err != nil {
+		return nil, fmt.Errorf("failed to parse rateLimit.default: %w", err)
+	}
+	var rules []rateLimitPolicyConfig
+	if err := cfg.UnmarshalKey("rateLimit.rules", &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse rateLimit.rules: %w", err)
+	}
+
+	rateLimitCfg := &request.RateLimitConfig{
+		Enabled: cfg.GetBool("rateLimit.enabled"),
+		Default: defaultPolicy.toPolicy(),
+		Timeout: time.Duration(cfg.GetInt("rateLimit.timeoutMs")) * time.Millisecond,
+	}
+	for i := range rules {
+		rateLimitCfg.Rules = append(rateLimitCfg.Rules, request.Rule{
+			Method: rules[i].Method,
+			Path:   rules[i].Path,
+			Role:   consts.Role(rules[i].Role),
+			Policy: rules[i].toPolicy(),
+		})
+	}
+	if err := rateLimitCfg.Default.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rateLimit.default: %w", err)
+	}
+	for i := range rateLimitCfg.Rules {
+		if err := rateLimitCfg.Rules[i].Policy.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid rateLimit.rules[%d]: %w", i, err)
+		}
+	}
+
+	primary := redisinfra.NewRateLimiter(client, keys)
+	fallback := request.NewRateLimiter(rateLimitCfg.Default.Limit, rateLimitCfg.Default.Window)
+
+	return request.NewDistributedRateLimiter(primary, fallback, rateLimitCfg), nil
+}
+
+// ProvideIdempotencyStore 提供 Idempotency-Key 回應儲存
+func ProvideIdempotencyStore(client redisclient.UniversalClient, keys *redisinfra.KeyBuilder) *redisinfra.IdempotencyStore {
+	return redisinfra.NewIdempotencyStore(client, keys)
+}
+
+// ProvideSessionRepository 提供登入 Session 儲存庫
+func ProvideSessionRepository(client redisclient.UniversalClient, keys *redisinfra.KeyBuilder) authrepo.ISessionRepository {
+	return redisinfra.NewSessionStore(client, keys.WithBC("auth", 1))
+}
+
+// ProvideJWTKeySet 提供 JWT 金鑰組
+// 未配置 jwt.keys 時以 jwt.secret 作為 HS256 金鑰（本地與開發環境）
+func ProvideJWTKeySet(cfg *viper.Viper) (*jwt.KeySet, error) {
+	keySetCfg := &jwt.KeySetConfig{
+		SigningKeyID: cfg.GetString("jwt.signingKeyId"),
+		Issuer:       cfg.GetString("jwt.issuer"),
+		Audience:     cfg.GetString("jwt.audience"),
+	}
+	if err := cfg.UnmarshalKey("jwt.keys", &keySetCfg.Keys); err != nil {
+		return nil, fmt.Errorf("failed to parse jwt.keys: %w", err)
+	}
+
+	if len(keySetCfg.Keys) == 0 {
+		keySetCfg.SigningKeyID = "default"
+		keySetCfg.Keys = []jwt.KeyConfig{{
+			ID:        keySetCfg.SigningKeyID,
+			Algorithm: jwt.AlgHS256,
+			Secret:    cfg.GetString("jwt.secret"),
+		}}
+	}
+
+	return jwt.NewKeySet(keySetCfg)
+}
+
+// ProvideUserRepository 提供用戶儲存庫
+func ProvideUserRepository(db *gorm.DB) authrepo.IUserRepository {
+	return mysqlrepo.NewUserRepository(db)
+}
+
+// ProvideTokenService 提供 Token 服務
+func ProvideTokenService(cfg *viper.Viper, keys *jwt.KeySet, sessionRepo authrepo.ISessionRepository, userRepo authrepo.IUserRepository) *authapp.TokenService {
+	return authapp.NewTokenService(&authapp.TokenConfig{
+		AccessExpireHours:  cfg.GetInt("jwt.expireHours"),
+		RefreshExpireHours: cfg.GetInt("jwt.refreshExpireHours"),
+	}, keys, sessionRepo, userRepo)
+}
+
+// ProvideVerificationTokenRepository 提供一次性驗證 Token 儲存庫
+func ProvideVerificationTokenRepository(client redisclient.UniversalClient, keys *redisinfra.KeyBuilder) authrepo.IVerificationTokenRepository {
+	return redisinfra.NewVerificationTokenStore(client, keys.WithBC("auth", 1))
+}
+
+// ProvideMailer 提供郵件寄送
+// 啟用 mail.queue 且工作佇列啟用時，郵件交由 notifications 佇列於背景寄送
+func ProvideMailer(cfg *viper.Viper, jobQueue *queue.Queue) (mail.Mailer, error) {
+	mailer, err := mail.New(&mail.Config{
+		Driver:  cfg.GetString("mail.driver"),
+		From:    cfg.GetString("mail.from"),
+		FileDir: cfg.GetString("mail.fileDir"),
+		SMTP: mail.SMTPConfig{
+			Host:     cfg.GetString("mail.smtp.host"),
+			Port:     cfg.GetInt("mail.smtp.port"),
+			Username: cfg.GetString("mail.smtp.username"),
+			Password: cfg.GetString("mail.smtp.password"),
+			Security: cfg.GetString("mail.smtp.security"),
+			Timeout:  time.Duration(cfg.GetInt("mail.smtp.timeoutSeconds")) * time.Second,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.GetBool("mail.queue") && cfg.GetBool("queue.enabled") {
+		return mail.NewQueuedMailer(jobQueue, mailer), nil
+	}
+	return mailer, nil
+}
+
+// ProvideEmailSender 提供郵件範本寄送
+func ProvideEmailSender(cfg *viper.Viper, mailer mail.Mailer) (authapp.EmailSender, error) {
+	renderer, err := mail.NewRenderer(cfg.GetString("mail.defaultLocale"))
+	if err != nil {
+		return nil, err
+	}
+	return mail.NewTemplateSender(renderer, mailer), nil
+}
+
+// ProvideVerificationService 提供 Email 驗證與重設密碼服務
+func ProvideVerificationService(
+	cfg *viper.Viper,
+	userRepo authrepo.IUserRepository,
+	tokenRepo authrepo.IVerificationTokenRepository,
+	domainSvc *authservice.AuthDomainService,
+	tokenService *authapp.TokenService,
+	emailSender authapp.EmailSender,
+) *authapp.VerificationService {
+	return authapp.NewVerificationService(&authapp.VerificationConfig{
+		FrontendURL:           cfg.GetString("auth.frontendURL"),
+		EmailVerificationTTL:  time.Duration(cfg.GetInt("auth.emailVerificationTTLHours")) * time.Hour,
+		PasswordResetTTL:      time.Duration(cfg.GetInt("auth.passwordResetTTLMinutes")) * time.Minute,
+		PasswordResetCooldown: time.Duration(cfg.GetInt("auth.passwordResetCooldownSeconds")) * time.Second,
+	}, userRepo, tokenRepo, domainSvc, tokenService, emailSender)
+}
+
+// ProvideMFAChallengeRepository 提供 MFA Challenge 儲存庫
+func ProvideMFAChallengeRepository(client redisclient.UniversalClient, keys *redisinfra.KeyBuilder) authrepo.IMFAChallengeRepository {
+	return redisinfra.NewMFAChallengeStore(client, keys.WithBC("auth", 1))
+}
+
+// ProvideMFAService 提供 MFA 服務
+// TOTP 金鑰加密金鑰優先讀取 auth.mfa.encryptionKeyFile（正式環境以 secret 掛載），內容為 base64 編碼的 32 bytes
+func ProvideMFAService(
+	cfg *viper.Viper,
+	userRepo authrepo.IUserRepository,
+	challengeRepo authrepo.IMFAChallengeRepository,
+	domainSvc *authservice.AuthDomainService,
+	tokenService *authapp.TokenService,
+	loginGuard *authapp.LoginGuard,
+) (*authapp.MFAService, error) {
+	encoded := cfg.GetString("auth.mfa.encryptionKey")
+	if file := cfg.GetString("auth.mfa.encryptionKeyFile"); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read auth.mfa.encryptionKeyFile: %w", err)
+		}
+		encoded = strings.TrimSpace(string(data))
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("auth.mfa encryption key must be base64 encoded 32 bytes")
+	}
+
+	var requiredRoles []consts.Role
+	for _, role := range cfg.GetStringSlice("auth.mfa.requiredRoles") {
+		if !consts.Role(role).IsValid() {
+			return nil, fmt.Errorf("invalid role %q in auth.mfa.requiredRoles", role)
+		}
+		requiredRoles = append(requiredRoles, consts.Role(role))
+	}
+
+	return authapp.NewMFAService(&authapp.MFAConfig{
+		Issuer:            cfg.GetString("auth.mfa.issuer"),
+		EncryptionKey:     key,
+		RequiredRoles:     requiredRoles,
+		ChallengeTTL:      time.Duration(cfg.GetInt("auth.mfa.challengeTTLSeconds")) * time.Second,
+		MaxAttempts:       cfg.GetInt("auth.mfa.maxAttempts"),
+		RecoveryCodeCount: cfg.GetInt("auth.mfa.recoveryCodeCount"),
+	}, userRepo, challengeRepo, domainSvc, tokenService, loginGuard), nil
+}
+
+// ProvideLoginAttemptRepository 提供登入失敗計數儲存庫
+func ProvideLoginAttemptRepository(client redisclient.UniversalClient, keys *redisinfra.KeyBuilder) authrepo.ILoginAttemptRepository {
+	return redisinfra.NewLoginAttemptStore(client, keys.WithBC("auth", 1))
+}
+
+// ProvideLoginGuard 提供登入節流
+func ProvideLoginGuard(cfg *viper.Viper, attemptRepo authrepo.ILoginAttemptRepository) *authapp.LoginGuard {
+	policy := func(key string) authentity.LockoutPolicy {
+		return authentity.LockoutPolicy{
+			FreeAttempts:  cfg.GetInt(key + ".freeAttempts"),
+			BaseDelay:     time.Duration(cfg.GetInt(key+".baseDelaySeconds")) * time.Second,
+			MaxDelay:      time.Duration(cfg.GetInt(key+".maxDelaySeconds")) * time.Second,
+			LockThreshold: cfg.GetInt(key + ".lockThreshold"),
+			LockDuration:  time.Duration(cfg.GetInt(key+".lockMinutes")) * time.Minute,
+			Window:        time.Duration(cfg.GetInt(key+".windowMinutes")) * time.Minute,
+		}
+	}
+
+	return authapp.NewLoginGuard(&authapp.LockoutConfig{
+		Enabled: cfg.GetBool("auth.lockout.enabled"),
+		Account: policy("auth.lockout.account"),
+		IP:      policy("auth.lockout.ip"),
+	}, attemptRepo)
+}
+
+// ProvideUserIdentityRepository 提供外部身份儲存庫
+func ProvideUserIdentityRepository(db *gorm.DB) authrepo.IUserIdentityRepository {
+	return mysqlrepo.NewUserIdentityRepository(db)
+}
+
+// ProvideOIDCStateRepository 提供 OIDC state 儲存
+func ProvideOIDCStateRepository(client redisclient.UniversalClient, keys *redisinfra.KeyBuilder) authrepo.IOIDCStateRepository {
+	return redisinfra.NewOIDCStateStore(client, keys.WithBC("auth", 1))
+}
+
+// ProvideIdentityProvider 提供 OIDC 身份提供者
+func ProvideIdentityProvider(cfg *viper.Viper) authapp.IdentityProvider {
+	return oidc.NewProvider(&oidc.Config{
+		IssuerURL:    cfg.GetString("auth.oidc.issuerURL"),
+		ClientID:     cfg.GetString("auth.oidc.clientID"),
+		ClientSecret: cfg.GetString("auth.oidc.clientSecret"),
+		RedirectURL:  cfg.GetString("auth.oidc.redirectURL"),
+		Scopes:       cfg.GetStringSlice("auth.oidc.scopes"),
+		GroupsClaim:  cfg.GetString("auth.oidc.groupsClaim"),
+		Timeout:      time.Duration(cfg.GetInt("auth.oidc.timeoutSeconds")) * time.Second,
+	})
+}
+
+// ProvideSSOService 提供單一登入服務
+func ProvideSSOService(
+	cfg *viper.Viper,
+	provider authapp.IdentityProvider,
+	userRepo authrepo.IUserRepository,
+	identityRepo authrepo.IUserIdentityRepository,
+	stateRepo authrepo.IOIDCStateRepository,
+	tokenService *authapp.TokenService,
+	mfaService *authapp.MFAService,
+) (*authapp.SSOService, error) {
+	var mappings []struct {
+		Group string
+		Role  string
+	}
+	if err := cfg.UnmarshalKey("auth.oidc.roleMappings", &mappings); err != nil {
+		return nil, fmt.Errorf("failed to parse auth.oidc.roleMappings: %w", err)
+	}
+
+	roleMappings := make([]authapp.RoleMapping, 0, len(mappings))
+	for _, m := range mappings {
+		if !consts.Role(m.Role).IsValid() {
+			return nil, fmt.Errorf("invalid role %q in auth.oidc.roleMappings", m.Role)
+		}
+		roleMappings = append(roleMappings, authapp.RoleMapping{Group: m.Group, Role: consts.Role(m.Role)})
+	}
+
+	defaultRole := consts.Role(cfg.GetString("auth.oidc.defaultRole"))
+	if !defaultRole.IsValid() {
+		return nil, fmt.Errorf("invalid auth.oidc.defaultRole %q", defaultRole)
+	}
+
+	return authapp.NewSSOService(&authapp.SSOConfig{
+		Enabled:       cfg.GetBool("auth.oidc.enabled"),
+		StateTTL:      time.Duration(cfg.GetInt("auth.oidc.stateTTLSeconds")) * time.Second,
+		AutoProvision: cfg.GetBool("auth.oidc.autoProvision"),
+		RoleMappings:  roleMappings,
+		DefaultRole:   defaultRole,
+	}, provider, userRepo, identityRepo, stateRepo, tokenService, mfaService), nil
+}
+
+// ProvideRoleRepository 提供角色與權限儲存庫（兩級快取）
+func ProvideRoleRepository(cfg *viper.Viper, db *gorm.DB, cache *redisinfra.TwoLevelCache, keys *redisinfra.KeyBuilder) authrepo.IRoleRepository {
+	ttl := time.Duration(cfg.GetInt("auth.permission.cacheTTLSeconds")) * time.Second
+	return redisinfra.NewCachedRoleRepository(mysqlrepo.NewRoleRepository(db), cache, keys.WithBC("auth", 1), ttl)
+}
+
+// authSet auth BC 的依賴
+var authSet = wire.NewSet(
+	ProvideJWTKeySet,
+	ProvideSessionRepository,
+	ProvideUserRepository,
+	ProvideVerificationTokenRepository,
+	ProvideMFAChallengeRepository,
+	ProvideLoginAttemptRepository,
+	ProvideUserIdentityRepository,
+	ProvideOIDCStateRepository,
+	ProvideRoleRepository,
+	ProvideIdentityProvider,
+	ProvideMailer,
+	ProvideEmailSender,
+	authservice.NewAuthDomainService,
+	ProvideTokenService,
+	ProvideVerificationService,
+	ProvideLoginGuard,
+	ProvideMFAService,
+	ProvideSSOService,
+	authapp.NewPermissionService,
+	authapp.NewAuthService,
+	authhttp.NewController,
+)
+
 // ProvideRouter 提供 Gin Router
-func ProvideRouter() *gin.Engine {
-	return webserver.SetupRouter()
+func ProvideRouter(cfg *viper.Viper, rateLimiter *request.DistributedRateLimiter, idempotencyStore *redisinfra.IdempotencyStore, tokenService *authapp.TokenService, permissionService *authapp.PermissionService, authController *authhttp.Controller, sched *scheduler.Scheduler) (*gin.Engine, error) {
+	routerCfg := &webserver.Config{
+		TrustedProxies:  cfg.GetStringSlice("app.trustedProxies"),
+		RemoteIPHeaders: cfg.GetStringSlice("app.remoteIPHeaders"),
+		TrustedPlatform: cfg.GetString("app.trustedPlatform"),
+		Idempotency: &request.IdempotencyConfig{
+			Enabled:      cfg.GetBool("idempotency.enabled"),
+			TTL:          time.Duration(cfg.GetInt("idempotency.ttlHours")) * time.Hour,
+			LockTTL:      time.Duration(cfg.GetInt("idempotency.lockTTLSeconds")) * time.Second,
+			MaxBodyBytes: cfg.GetInt("idempotency.maxBodyBytes"),
+			Timeout:      time.Duration(cfg.GetInt("idempotency.timeoutMs")) * time.Millisecond,
+		},
+	}
+
+	return webserver.SetupRouter(routerCfg, rateLimiter, idempotencyStore, tokenService, permissionService, authController, scheduler.NewHandler(sched))
 }
 
 // App 應用程式結構
 type App struct {
-	Config  *viper.Viper
-	Logger  *zap.Logger
-	MySQL   *gorm.DB
-	MongoDB *mongo.Database
-	Redis   *redisclient.Client
-	Router  *gin.Engine
+	Config   *viper.Viper
+	Logger   *zap.Logger
+	MySQL    *gorm.DB
+	MongoDB  *mongo.Database
+	Redis    redisclient.UniversalClient
+	Cache    *redisinfra.Cache
+	TwoLevel *redisinfra.TwoLevelCache
+	Locker   *redisinfra.Locker
+	MQTT     mqtt.Client
+	Router   *gin.Engine
+
+	OutboxRelay  *event.Relay
+	ChangeStream *mongodb.ChangeStreamSubscriber
+	JobQueue     *queue.Queue
+	Scheduler    *scheduler.Scheduler
 }
 
 // newApp 創建 App 實例
@@ -109,16 +642,33 @@ func newApp(
 	logger *zap.Logger,
 	mysql *gorm.DB,
 	mongodb *mongo.Database,
-	redis *redisclient.Client,
+	redis redisclient.UniversalClient,
+	cache *redisinfra.Cache,
+	twoLevel *redisinfra.TwoLevelCache,
+	locker *redisinfra.Locker,
+	mqttClient mqtt.Client,
 	router *gin.Engine,
+	outboxRelay *event.Relay,
+	changeStream *mongodb.ChangeStreamSubscriber,
+	jobQueue *queue.Queue,
+	sched *scheduler.Scheduler,
 ) *App {
 	return &App{
-		Config:  config,
-		Logger:  logger,
-		MySQL:   mysql,
-		MongoDB: mongodb,
-		Redis:   redis,
-		Router:  router,
+		Config:   config,
+		Logger:   logger,
+		MySQL:    mysql,
+		MongoDB:  mongodb,
+		Redis:    redis,
+		Cache:    cache,
+		TwoLevel: twoLevel,
+		Locker:   locker,
+		MQTT:     mqttClient,
+		Router:   router,
+
+		OutboxRelay:  outboxRelay,
+		ChangeStream: changeStream,
+		JobQueue:     jobQueue,
+		Scheduler:    sched,
 	}
 }
 
@@ -135,10 +685,31 @@ func InitServer() (*App, error) {
 		ProvideMySQL,
 		ProvideMongoDB,
 		ProvideRedis,
+		ProvideCacheKeys,
+		ProvideCache,
+		ProvideTwoLevelCache,
+		ProvideLocker,
+		ProvideChangeStreamSubscriber,
+
+		// Broker
+		ProvideMQTT,
+
+		// Event
+		ProvideEventPublisher,
+		ProvideOutboxRelay,
+
+		// Queue
+		ProvideJobQueue,
+		ProvideScheduler,
 
 		// Router
+		ProvideRateLimiter,
+		ProvideIdempotencyStore,
 		ProvideRouter,
 
+		// Auth
+		authSet,
+
 		// App
 		newApp,
 	))
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"sync_drive_backend/configs"
+	"sync_drive_backend/internal/infrastructure/persistence/mongodb"
+	"sync_drive_backend/internal/infrastructure/persistence/mongodb/record"
+)
+
+// MongoDB 集合與索引同步工具
+// 比對 record 中宣告的集合與索引，建立缺少的項目並回報差異
+//
+//	ENV=dev go run ./cmd/mongoschema/ -dry-run
+//	ENV=dev go run ./cmd/mongoschema/ -drop-stale
+func main() {
+	dropStale := flag.Bool("drop-stale", false, "drop undeclared indexes and recreate changed ones")
+	dryRun := flag.Bool("dry-run", false, "only report differences without applying changes")
+	flag.Parse()
+
+	if err := configs.Load(); err != nil {
+		fmt.Printf("Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	db, err := mongodb.Init(&mongodb.Config{
+		URI:      configs.GetString("mongodb.uri"),
+		Database: configs.GetString("mongodb.database"),
+		Timeout:  configs.GetInt("mongodb.timeout"),
+	})
+	if err != nil {
+		fmt.Printf("Failed to connect mongodb: %v\n", err)
+		os.Exit(1)
+	}
+	defer func() { _ = db.Client().Disconnect(context.Background()) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	report, err := mongodb.SyncSchema(ctx, db, record.Collections(), mongodb.SyncOptions{
+		DropStale: *dropStale,
+		DryRun:    *dryRun,
+	})
+	if report != nil {
+		printReport(report, *dryRun)
+	}
+	if err != nil {
+		fmt.Printf("Failed to sync schema: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// printReport 輸出同步結果
+func printReport(report *mongodb.SchemaReport, dryRun bool) {
+	if !report.HasDrift() {
+		fmt.Println("Schema is up to date")
+		return
+	}
+
+	prefix := ""
+	if dryRun {
+		prefix = "[dry-run] "
+	}
+
+	sections := []struct {
+		title string
+		items []string
+	}{
+		{"Created collections", report.CreatedCollections},
+		{"Created indexes", report.CreatedIndexes},
+		{"Changed indexes", report.ChangedIndexes},
+		{"Stale indexes", report.StaleIndexes},
+		{"Updated validators", report.UpdatedValidators},
+		{"Warnings", report.Warnings},
+	}
+
+	for _, s := range sections {
+		if len(s.items) == 0 {
+			continue
+		}
+		fmt.Printf("%s%s:\n", prefix, s.title)
+		for _, item := range s.items {
+			fmt.Printf("  - %s\n", item)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+-- 交易式 Outbox：與業務資料同一個交易寫入，由 Relay 背景發佈
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id           BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    message_id   CHAR(36)        NOT NULL COMMENT '訊息 ID，供消費端去重',
+    topic        VARCHAR(255)    NOT NULL,
+    event_type   VARCHAR(128)    NOT NULL,
+    payload      JSON            NOT NULL,
+    status       VARCHAR(16)     NOT NULL DEFAULT 'pending' COMMENT 'pending / sent / failed',
+    attempts     INT             NOT NULL DEFAULT 0,
+    last_error   VARCHAR(1024)   NOT NULL DEFAULT '',
+    available_at DATETIME(3)     NOT NULL COMMENT '下次可發佈時間（重試退避）',
+    sent_at      DATETIME(3)     NULL,
+    created_at   DATETIME(3)     NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_outbox_message_id (message_id),
+    KEY idx_outbox_status_available (status, available_at),
+    KEY idx_outbox_status_sent (status, sent_at)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
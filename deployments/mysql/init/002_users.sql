@@ -0,0 +1,25 @@
+-- 用戶（auth BC）
+CREATE TABLE IF NOT EXISTS users (
+    id                  CHAR(36)      NOT NULL,
+    username            VARCHAR(64)   NOT NULL,
+    email               VARCHAR(255)  NOT NULL,
+    password_hash       VARCHAR(255)  NOT NULL COMMENT 'bcrypt',
+    display_name        VARCHAR(128)  NOT NULL DEFAULT '',
+    phone               VARCHAR(32)   NOT NULL DEFAULT '',
+    role                VARCHAR(32)   NOT NULL COMMENT 'admin / user / vehicle / device',
+    status              VARCHAR(16)   NOT NULL DEFAULT 'active' COMMENT 'active / disabled',
+    email_verified      TINYINT(1)    NOT NULL DEFAULT 0,
+    password_changed_at DATETIME(3)   NOT NULL,
+    last_login_at       DATETIME(3)   NULL,
+    created_at          DATETIME(3)   NOT NULL,
+    updated_at          DATETIME(3)   NOT NULL,
+    version             BIGINT        NOT NULL DEFAULT 1 COMMENT '樂觀鎖版本號',
+    created_by          VARCHAR(64)   NOT NULL DEFAULT '',
+    updated_by          VARCHAR(64)   NOT NULL DEFAULT '',
+    deleted_at          DATETIME(3)   NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_users_username (username),
+    UNIQUE KEY uk_users_email (email),
+    KEY idx_users_role (role),
+    KEY idx_users_deleted_at (deleted_at)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
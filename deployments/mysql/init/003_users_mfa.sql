@@ -0,0 +1,6 @@
+-- 用戶 TOTP 多因素驗證
+ALTER TABLE users
+    ADD COLUMN mfa_enabled        TINYINT(1)   NOT NULL DEFAULT 0 AFTER last_login_at,
+    ADD COLUMN mfa_secret         VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'AES-GCM 加密的 TOTP 金鑰' AFTER mfa_enabled,
+    ADD COLUMN mfa_last_counter   BIGINT       NOT NULL DEFAULT 0 COMMENT '最後使用的 TOTP 時間步' AFTER mfa_secret,
+    ADD COLUMN mfa_recovery_codes JSON         NULL COMMENT '未使用的復原碼 SHA256' AFTER mfa_last_counter;
@@ -0,0 +1,12 @@
+-- 用戶綁定的外部身份（OIDC 單一登入）
+CREATE TABLE IF NOT EXISTS user_identities (
+    id         CHAR(36)     NOT NULL,
+    user_id    CHAR(36)     NOT NULL,
+    provider   VARCHAR(255) NOT NULL COMMENT 'OIDC issuer',
+    subject    VARCHAR(255) NOT NULL COMMENT 'OIDC sub',
+    email      VARCHAR(255) NOT NULL DEFAULT '',
+    created_at DATETIME(3)  NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_user_identities_provider_subject (provider, subject),
+    KEY idx_user_identities_user_id (user_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,46 @@
+-- 角色與權限（auth BC），由管理員於執行期間調整
+CREATE TABLE IF NOT EXISTS roles (
+    name        VARCHAR(32)  NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    built_in    TINYINT(1)   NOT NULL DEFAULT 0 COMMENT '內建角色不可刪除',
+    created_at  DATETIME(3)  NOT NULL,
+    updated_at  DATETIME(3)  NOT NULL,
+    PRIMARY KEY (name)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS permissions (
+    code        VARCHAR(64)  NOT NULL COMMENT '{resource}:{action}，* 表示全部',
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at  DATETIME(3)  NOT NULL,
+    PRIMARY KEY (code)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role       VARCHAR(32) NOT NULL,
+    permission VARCHAR(64) NOT NULL,
+    PRIMARY KEY (role, permission),
+    KEY idx_role_permissions_permission (permission)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+INSERT IGNORE INTO roles (name, description, built_in, created_at, updated_at) VALUES
+    ('admin',   '系統管理員', 1, NOW(3), NOW(3)),
+    ('user',    '一般用戶',   1, NOW(3), NOW(3)),
+    ('vehicle', '車輛',       1, NOW(3), NOW(3)),
+    ('device',  '設備',       1, NOW(3), NOW(3));
+
+INSERT IGNORE INTO permissions (code, description, created_at) VALUES
+    ('*',            '全部權限',                   NOW(3)),
+    ('order:read',   '查詢訂單',                   NOW(3)),
+    ('order:create', '建立訂單',                   NOW(3)),
+    ('order:assign', '指派訂單',                   NOW(3)),
+    ('vehicle:read', '查詢車輛',                   NOW(3)),
+    ('vehicle:edit', '編輯車輛',                   NOW(3)),
+    ('user:manage',  '管理用戶（解除鎖定、指派角色）', NOW(3)),
+    ('role:manage',  '管理角色與權限',             NOW(3)),
+    ('scheduler:manage', '管理排程工作（查詢、觸發、暫停）', NOW(3));
+
+INSERT IGNORE INTO role_permissions (role, permission) VALUES
+    ('admin', '*'),
+    ('user',  'order:read'),
+    ('user',  'order:create'),
+    ('user',  'vehicle:read');
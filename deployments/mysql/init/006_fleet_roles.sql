@@ -0,0 +1,11 @@
+-- 資源層級授權使用的內建角色，存取範圍由程式碼中的授權策略限制
+INSERT IGNORE INTO roles (name, description, built_in, created_at, updated_at) VALUES
+    ('driver',        '司機',       1, NOW(3), NOW(3)),
+    ('fleet_manager', '車隊管理者', 1, NOW(3), NOW(3));
+
+INSERT IGNORE INTO role_permissions (role, permission) VALUES
+    ('driver',        'order:read'),
+    ('fleet_manager', 'order:read'),
+    ('fleet_manager', 'order:assign'),
+    ('fleet_manager', 'vehicle:read'),
+    ('fleet_manager', 'vehicle:edit');
@@ -0,0 +1,46 @@
+package consts
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Permission 權限代碼，格式為 {resource}:{action}
+// 角色與權限的對應存放於資料庫，可於執行期間由管理員調整；此處僅列出程式碼中檢查的權限
+type Permission string
+
+const (
+	PermissionAll Permission = "*" // 全部權限
+
+	PermissionOrderRead   Permission = "order:read"   // 查詢訂單
+	PermissionOrderCreate Permission = "order:create" // 建立訂單
+	PermissionOrderAssign Permission = "order:assign" // 指派訂單
+	PermissionVehicleRead Permission = "vehicle:read" // 查詢車輛
+	PermissionVehicleEdit Permission = "vehicle:edit" // 編輯車輛
+
+	PermissionUserManage      Permission = "user:manage"      // 管理用戶（解除鎖定、指派角色）
+	PermissionRoleManage      Permission = "role:manage"      // 管理角色與權限
+	PermissionSchedulerManage Permission = "scheduler:manage" // 管理排程工作（查詢、觸發、暫停）
+)
+
+// permissionPattern 權限代碼格式，action 可為 * 表示該資源的全部操作
+var permissionPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}:(\*|[a-z][a-z0-9_]{0,31})$`)
+
+// IsValid 驗證權限代碼格式
+func (p Permission) IsValid() bool {
+	return p == PermissionAll || permissionPattern.MatchString(string(p))
+}
+
+// Covers 是否涵蓋指定權限（相同、* 或 {resource}:*）
+func (p Permission) Covers(target Permission) bool {
+	if p == target || p == PermissionAll {
+		return true
+	}
+	resource, action, ok := strings.Cut(string(p), ":")
+	return ok && action == "*" && strings.HasPrefix(string(target), resource+":")
+}
+
+// String 返回權限字串
+func (p Permission) String() string {
+	return string(p)
+}
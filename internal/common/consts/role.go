@@ -8,12 +8,15 @@ const (
 	RoleUser    Role = "user"    // 一般用戶
 	RoleVehicle Role = "vehicle" // 車輛
 	RoleDevice  Role = "device"  // 設備
+
+	RoleDriver       Role = "driver"        // 司機，只能存取指派給自己的訂單
+	RoleFleetManager Role = "fleet_manager" // 車隊管理者，只能存取所屬車隊的車輛與訂單
 )
 
 // IsValid 驗證角色是否有效
 func (r Role) IsValid() bool {
 	switch r {
-	case RoleAdmin, RoleUser, RoleVehicle, RoleDevice:
+	case RoleAdmin, RoleUser, RoleVehicle, RoleDevice, RoleDriver, RoleFleetManager:
 		return true
 	default:
 		return false
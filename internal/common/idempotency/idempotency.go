@@ -0,0 +1,26 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Record 冪等紀錄
+type Record struct {
+	Fingerprint string      // 請求內容雜湊（方法 + 路徑 + 查詢參數 + body）
+	Completed   bool        // false 表示原始請求仍在處理中
+	Status      int         `json:",omitempty"`
+	Header      http.Header `json:",omitempty"`
+	Body        []byte      `json:",omitempty"`
+}
+
+// Store 冪等紀錄儲存
+type Store interface {
+	// Begin 佔用 key 並標記為處理中；key 已存在時返回既有紀錄且 acquired 為 false
+	Begin(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (existing *Record, acquired bool, err error)
+	// Complete 保存回應，之後的重試直接重播
+	Complete(ctx context.Context, key string, record *Record, ttl time.Duration) error
+	// Release 釋放 key，讓客戶端可以重新送出
+	Release(ctx context.Context, key string) error
+}
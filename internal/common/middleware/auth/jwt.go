@@ -1,8 +1,10 @@
 package auth
 
 import (
+	"context"
 	"strings"
 
+	"sync_drive_backend/internal/common/util"
 	"sync_drive_backend/pkg/errors"
 	"sync_drive_backend/pkg/jwt"
 
@@ -16,10 +18,22 @@ const (
 	ContextKeyUsername = "username"
 	// ContextKeyRoleID 角色 ID 的 context key
 	ContextKeyRoleID = "role_id"
+	// ContextKeySessionID 登入 Session ID 的 context key
+	ContextKeySessionID = "session_id"
+	// ContextKeyAPIKey 已通過驗證的 API Key 的 context key，由 API Key 驗證中介層寫入
+	ContextKeyAPIKey = "api_key"
 )
 
+// TokenVerifier Access Token 驗證
+type TokenVerifier interface {
+	// Parse 僅驗證簽章與時效
+	Parse(token string) (*jwt.Claims, error)
+	// Verify 驗證簽章與時效，並檢查 Token 是否已被撤銷（登出、撤銷 Session、全部登出）
+	Verify(ctx context.Context, token string) (*jwt.Claims, error)
+}
+
 // JWTAuth JWT 驗證中介層
-func JWTAuth(secret string) gin.HandlerFunc {
+func JWTAuth(verifier TokenVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 從 Header 取得 Token
 		authHeader := c.GetHeader("Authorization")
@@ -30,32 +44,64 @@ func JWTAuth(secret string) gin.HandlerFunc {
 		}
 
 		// 驗證格式：Bearer {token}
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) != 2 || parts[0] != "Bearer" {
+		token, ok := bearerToken(authHeader)
+		if !ok {
 			errors.HandleError(c, errors.New(errors.ErrUnauthorized, "invalid authorization format"))
 			c.Abort()
 			return
 		}
 
-		token := parts[1]
-
 		// 解析 Token
-		claims, err := jwt.Parse(token, secret)
+		claims, err := verifier.Verify(c.Request.Context(), token)
 		if err != nil {
-			errors.HandleError(c, errors.New(errors.ErrUnauthorized, "invalid or expired token"))
+			errors.HandleError(c, err)
 			c.Abort()
 			return
 		}
 
-		// 將用戶資訊存入 Context
-		c.Set(ContextKeyUserID, claims.UserID)
-		c.Set(ContextKeyUsername, claims.Username)
-		c.Set(ContextKeyRoleID, claims.RoleId)
+		setClaims(c, claims)
+
+		c.Next()
+	}
+}
+
+// OptionalJWTAuth 可選的 JWT 解析中介層
+// 帶有效 Token 時寫入用戶資訊，否則以匿名身分繼續，不會中斷請求
+// 用於全局中介層（例如限流）需要在 JWTAuth 之前辨識用戶與角色，只驗證簽章不檢查撤銷，需要驗證的路由仍須套用 JWTAuth
+func OptionalJWTAuth(verifier TokenVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token, ok := bearerToken(c.GetHeader("Authorization")); ok {
+			if claims, err := verifier.Parse(token); err == nil {
+				setClaims(c, claims)
+			}
+		}
 
 		c.Next()
 	}
 }
 
+// bearerToken 取出 Bearer Token
+func bearerToken(authHeader string) (string, bool) {
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || parts[0] != "Bearer" || parts[1] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// setClaims 將用戶資訊存入 Context
+func setClaims(c *gin.Context, claims *jwt.Claims) {
+	c.Set(ContextKeyUserID, claims.UserID)
+	c.Set(ContextKeyUsername, claims.Username)
+	c.Set(ContextKeyRoleID, claims.RoleId)
+	c.Set(ContextKeySessionID, claims.SessionID)
+
+	// 同步寫入 request context，供 Repository 等下游取得操作者，供授權策略取得角色
+	ctx := util.WithUserID(c.Request.Context(), claims.UserID)
+	ctx = util.WithRoleID(ctx, claims.RoleId)
+	c.Request = c.Request.WithContext(ctx)
+}
+
 // GetUserID 從 Context 取得用戶 ID
 func GetUserID(c *gin.Context) string {
 	if userID, exists := c.Get(ContextKeyUserID); exists {
@@ -79,3 +125,19 @@ func GetRoleID(c *gin.Context) string {
 	}
 	return ""
 }
+
+// GetSessionID 從 Context 取得登入 Session ID
+func GetSessionID(c *gin.Context) string {
+	if sessionID, exists := c.Get(ContextKeySessionID); exists {
+		return sessionID.(string)
+	}
+	return ""
+}
+
+// GetAPIKey 從 Context 取得已通過驗證的 API Key，未經驗證的 X-API-Key header 不會寫入
+func GetAPIKey(c *gin.Context) string {
+	if apiKey, exists := c.Get(ContextKeyAPIKey); exists {
+		return apiKey.(string)
+	}
+	return ""
+}
@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"context"
+
 	"sync_drive_backend/internal/common/consts"
 	"sync_drive_backend/pkg/errors"
 
@@ -27,7 +29,7 @@ func RequireRole(allowedRoles ...consts.Role) gin.HandlerFunc {
 		}
 
 		// 無權限
-		errors.HandleError(c, errors.New(errors.ErrUnauthorized, "insufficient permissions"))
+		errors.HandleError(c, errors.New(errors.ErrForbidden, "insufficient permissions"))
 		c.Abort()
 	}
 }
@@ -36,3 +38,49 @@ func RequireRole(allowedRoles ...consts.Role) gin.HandlerFunc {
 func RequireAdmin() gin.HandlerFunc {
 	return RequireRole(consts.RoleAdmin)
 }
+
+// PermissionChecker 角色權限查詢
+type PermissionChecker interface {
+	// HasPermission 角色是否具備指定權限
+	HasPermission(ctx context.Context, role consts.Role, permission consts.Permission) (bool, error)
+}
+
+// Authorizer 以角色權限控制路由
+type Authorizer struct {
+	checker PermissionChecker
+}
+
+// NewAuthorizer 創建 Authorizer
+func NewAuthorizer(checker PermissionChecker) *Authorizer {
+	return &Authorizer{checker: checker}
+}
+
+// RequirePermission 權限控制中介層：要求具備全部指定權限，需套用於 JWTAuth 之後
+//
+//	orders.POST("/:id/assign", authz.RequirePermission(consts.PermissionOrderAssign), ctl.Assign)
+func (a *Authorizer) RequirePermission(permissions ...consts.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roleID := GetRoleID(c)
+		if roleID == "" {
+			errors.HandleError(c, errors.New(errors.ErrUnauthorized, "user role not found"))
+			c.Abort()
+			return
+		}
+
+		for _, permission := range permissions {
+			ok, err := a.checker.HasPermission(c.Request.Context(), consts.Role(roleID), permission)
+			if err != nil {
+				errors.HandleError(c, err)
+				c.Abort()
+				return
+			}
+			if !ok {
+				errors.HandleError(c, errors.New(errors.ErrForbidden, "insufficient permissions"))
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,23 @@
+package request
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NoStore 禁止快取回應，用於含 Token、密鑰或復原碼等憑證的端點
+// 標記 no-store 的回應也不會被 Idempotency 保存與重播
+func NoStore() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "no-store")
+		c.Header("Pragma", "no-cache")
+		c.Next()
+	}
+}
+
+// isNoStore 回應是否禁止保存
+func isNoStore(header http.Header) bool {
+	return strings.Contains(strings.ToLower(header.Get("Cache-Control")), "no-store")
+}
@@ -0,0 +1,242 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"sync_drive_backend/internal/common/idempotency"
+	"sync_drive_backend/internal/common/middleware/auth"
+	"sync_drive_backend/pkg/errors"
+	"sync_drive_backend/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// HeaderIdempotencyKey 冪等鍵 header
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed 回應為重播結果時設定
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLength 冪等鍵長度上限
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyConfig 冪等中介層配置
+type IdempotencyConfig struct {
+	Enabled      bool
+	TTL          time.Duration // 回應保留時間
+	LockTTL      time.Duration // 處理中狀態的存活時間，超過後視為中斷並允許重試
+	MaxBodyBytes int           // 回應 body 超過此大小時不保存；請求 body 超過此大小時暫存於檔案計算雜湊
+	Timeout      time.Duration // 單次存取逾時
+}
+
+// Idempotency Idempotency-Key 中介層
+// 對帶有 Idempotency-Key 的 POST / PUT / PATCH / DELETE 請求，以用戶 + key + 路由保存第一次的回應並重播給重試的請求；
+// 原始請求仍在處理中返回 409，相同 key 但請求內容不同返回 422；5xx 回應不保存，允許客戶端重試
+// 標記 Cache-Control: no-store 的回應（見 NoStore）含有憑證，同樣不保存，避免 Token 寫入 Redis
+// Redis 不可用時不做冪等檢查直接處理請求
+func Idempotency(store idempotency.Store, cfg *IdempotencyConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderIdempotencyKey)
+		if !cfg.Enabled || key == "" || !isUnsafeMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			errors.HandleError(c, errors.New(errors.ErrInvalidParams, "idempotency key too long"))
+			c.Abort()
+			return
+		}
+
+		fingerprint, cleanup, err := fingerprintRequest(c, cfg.MaxBodyBytes)
+		defer cleanup()
+		if err != nil {
+			errors.HandleError(c, errors.Wrap(errors.ErrInternalError, "failed to read request body", err))
+			c.Abort()
+			return
+		}
+
+		storeKey := idempotencyScope(c) + ":" + c.Request.Method + ":" + c.FullPath() + ":" + key
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), cfg.Timeout)
+		existing, acquired, err := store.Begin(ctx, storeKey, fingerprint, cfg.LockTTL)
+		cancel()
+		if err != nil {
+			logger.Warn("Idempotency store unavailable, processing request without idempotency", zap.Error(err))
+			c.Next()
+			return
+		}
+
+		if !acquired {
+			switch {
+			case existing.Fingerprint != fingerprint:
+				errors.HandleError(c, errors.New(errors.ErrIdempotencyKeyReuse, "idempotency key already used with a different request"))
+			case !existing.Completed:
+				errors.HandleError(c, errors.New(errors.ErrRequestInProgress, "request with this idempotency key is still in progress"))
+			default:
+				replay(c, existing)
+			}
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer, limit: cfg.MaxBodyBytes}
+		c.Writer = recorder
+
+		c.Next()
+
+		// 使用獨立的 context，避免客戶端中斷連線後紀錄停留在處理中
+		ctx, cancel = context.WithTimeout(context.Background(), cfg.Timeout)
+		defer cancel()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError || recorder.overflow || isNoStore(recorder.Header()) {
+			if err := store.Release(ctx, storeKey); err != nil {
+				logger.Warn("Failed to release idempotency key", zap.Error(err))
+			}
+			return
+		}
+
+		record := &idempotency.Record{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			Status:      status,
+			Header:      replayableHeader(recorder.Header()),
+			Body:        recorder.body.Bytes(),
+		}
+		if err := store.Complete(ctx, storeKey, record, cfg.TTL); err != nil {
+			logger.Warn("Failed to save idempotent response", zap.Error(err))
+		}
+	}
+}
+
+// replay 重播保存的回應
+func replay(c *gin.Context, record *idempotency.Record) {
+	for name, values := range record.Header {
+		for _, v := range values {
+			c.Writer.Header().Add(name, v)
+		}
+	}
+	c.Header(HeaderIdempotentReplayed, "true")
+	c.Status(record.Status)
+	_, _ = c.Writer.Write(record.Body)
+}
+
+// idempotencyScope 冪等鍵的擁有者，未驗證的請求以 IP 區分
+func idempotencyScope(c *gin.Context) string {
+	if userID := auth.GetUserID(c); userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// isUnsafeMethod 是否為會改變狀態的方法
+func isUnsafeMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// fingerprintRequest 計算請求內容（方法、路徑、查詢參數與 body）雜湊並還原 body
+// body 超過 memLimit 時暫存於檔案（例如簽收照片上傳），避免整份載入記憶體
+func fingerprintRequest(c *gin.Context, memLimit int) (string, func(), error) {
+	noop := func() {}
+
+	h := sha256.New()
+	h.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "?" + c.Request.URL.RawQuery + "\n"))
+
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return hex.EncodeToString(h.Sum(nil)), noop, nil
+	}
+
+	var buf bytes.Buffer
+	n, err := io.CopyN(io.MultiWriter(&buf, h), c.Request.Body, int64(memLimit)+1)
+	if err != nil && err != io.EOF {
+		return "", noop, err
+	}
+	if n <= int64(memLimit) {
+		c.Request.Body = io.NopCloser(&buf)
+		return hex.EncodeToString(h.Sum(nil)), noop, nil
+	}
+
+	f, err := os.CreateTemp("", "idempotency-*")
+	if err != nil {
+		return "", noop, err
+	}
+	cleanup := func() {
+		f.Close()
+		os.Remove(f.Name())
+	}
+
+	if _, err := buf.WriteTo(f); err != nil {
+		return "", cleanup, err
+	}
+	if _, err := io.Copy(io.MultiWriter(f, h), c.Request.Body); err != nil {
+		return "", cleanup, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", cleanup, err
+	}
+	c.Request.Body = f
+
+	return hex.EncodeToString(h.Sum(nil)), cleanup, nil
+}
+
+// replayableHeader 保存需要重播的 header，排除每次請求各自產生的 header
+func replayableHeader(header http.Header) http.Header {
+	saved := make(http.Header, len(header))
+	for name, values := range header {
+		canonical := http.CanonicalHeaderKey(name)
+		if canonical == HeaderRequestID || canonical == "Retry-After" || canonical == "Date" ||
+			strings.HasPrefix(canonical, "X-Ratelimit-") {
+			continue
+		}
+		saved[canonical] = append([]string(nil), values...)
+	}
+	return saved
+}
+
+// responseRecorder 記錄回應 body 的 ResponseWriter
+type responseRecorder struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	limit    int
+	overflow bool // 超過上限，回應不保存
+}
+
+// Write 寫入回應並記錄
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.record(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString 寫入回應並記錄
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.record([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+// record 記錄 body，超過上限時放棄
+func (w *responseRecorder) record(b []byte) {
+	if w.overflow {
+		return
+	}
+	if w.body.Len()+len(b) > w.limit {
+		w.overflow = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(b)
+}
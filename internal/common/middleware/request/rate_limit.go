@@ -1,27 +1,40 @@
 package request
 
 import (
+	"context"
 	"net/http"
 	"sync"
 	"time"
 
+	"sync_drive_backend/internal/common/ratelimit"
+
 	"github.com/gin-gonic/gin"
 )
 
 // RateLimiter 簡單的記憶體限流器
+// 以 Sliding Window Counter 計數，每個 key 只保存前後兩個窗口的計數
+// 僅限單一副本，多副本部署時作為 Redis 限流器不可用時的降級方案
 type RateLimiter struct {
 	mu       sync.Mutex
-	requests map[string][]time.Time
+	counters map[string]*windowCounter
 	limit    int           // 時間窗口內的最大請求數
 	window   time.Duration // 時間窗口大小
 }
 
+// windowCounter 單一 key 的窗口計數
+type windowCounter struct {
+	window   time.Duration
+	index    int64 // 目前窗口編號
+	current  int
+	previous int
+}
+
 // NewRateLimiter 創建限流器
 // limit: 時間窗口內允許的最大請求數
 // window: 時間窗口大小（例如：1 分鐘）
 func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
 	rl := &RateLimiter{
-		requests: make(map[string][]time.Time),
+		counters: make(map[string]*windowCounter),
 		limit:    limit,
 		window:   window,
 	}
@@ -34,11 +47,13 @@ func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
 
 // RateLimit 限流中介層
 func (rl *RateLimiter) RateLimit() gin.HandlerFunc {
+	policy := &ratelimit.Policy{Limit: rl.limit, Window: rl.window}
+
 	return func(c *gin.Context) {
 		// 使用客戶端 IP 作為限流 key
 		key := c.ClientIP()
 
-		if !rl.allow(key) {
+		if result := rl.allow(key, policy); !result.Allowed {
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"code":    http.StatusTooManyRequests,
 				"message": "too many requests",
@@ -51,35 +66,52 @@ func (rl *RateLimiter) RateLimit() gin.HandlerFunc {
 	}
 }
 
+// Allow 檢查是否允許請求（實作 Limiter，不論策略的演算法皆以 Sliding Window Counter 計算）
+func (rl *RateLimiter) Allow(_ context.Context, key string, policy *ratelimit.Policy) (*ratelimit.Result, error) {
+	return rl.allow(policy.Name+":"+key, policy), nil
+}
+
 // allow 檢查是否允許請求
-func (rl *RateLimiter) allow(key string) bool {
+func (rl *RateLimiter) allow(key string, policy *ratelimit.Policy) *ratelimit.Result {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
 	now := time.Now()
-	windowStart := now.Add(-rl.window)
+	index := now.UnixNano() / int64(policy.Window)
+	elapsed := time.Duration(now.UnixNano() % int64(policy.Window))
+
+	// 取得該 key 的計數並推進窗口
+	counter, ok := rl.counters[key]
+	if !ok {
+		counter = &windowCounter{window: policy.Window, index: index}
+		rl.counters[key] = counter
+	}
+	switch counter.index {
+	case index:
+	case index - 1:
+		counter.previous, counter.current = counter.current, 0
+	default:
+		counter.previous, counter.current = 0, 0
+	}
+	counter.index = index
 
-	// 取得該 key 的請求記錄
-	requests := rl.requests[key]
+	// 以上一個窗口剩餘比例加權估算目前窗口內的請求數
+	weight := float64(policy.Window-elapsed) / float64(policy.Window)
+	estimated := float64(counter.previous)*weight + float64(counter.current)
 
-	// 過濾掉時間窗口外的請求
-	var validRequests []time.Time
-	for _, req := range requests {
-		if req.After(windowStart) {
-			validRequests = append(validRequests, req)
-		}
-	}
+	result := &ratelimit.Result{Limit: policy.Limit, ResetAfter: policy.Window - elapsed}
 
 	// 檢查是否超過限制
-	if len(validRequests) >= rl.limit {
-		return false
+	if estimated+1 > float64(policy.Limit) {
+		result.RetryAfter = policy.Window - elapsed
+		return result
 	}
 
 	// 記錄本次請求
-	validRequests = append(validRequests, now)
-	rl.requests[key] = validRequests
-
-	return true
+	counter.current++
+	result.Allowed = true
+	result.Remaining = int(float64(policy.Limit) - estimated - 1)
+	return result
 }
 
 // cleanup 定期清理過期的記錄
@@ -89,21 +121,11 @@ func (rl *RateLimiter) cleanup() {
 
 	for range ticker.C {
 		rl.mu.Lock()
-		now := time.Now()
-		windowStart := now.Add(-rl.window)
-
-		for key, requests := range rl.requests {
-			var validRequests []time.Time
-			for _, req := range requests {
-				if req.After(windowStart) {
-					validRequests = append(validRequests, req)
-				}
-			}
+		now := time.Now().UnixNano()
 
-			if len(validRequests) == 0 {
-				delete(rl.requests, key)
-			} else {
-				rl.requests[key] = validRequests
+		for key, counter := range rl.counters {
+			if now/int64(counter.window)-counter.index > 1 {
+				delete(rl.counters, key)
 			}
 		}
 		rl.mu.Unlock()
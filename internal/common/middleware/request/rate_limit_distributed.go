@@ -0,0 +1,107 @@
+package request
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"sync_drive_backend/internal/common/ratelimit"
+	"sync_drive_backend/pkg/errors"
+	"sync_drive_backend/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RateLimitConfig 分散式限流配置
+type RateLimitConfig struct {
+	Enabled bool
+	Default ratelimit.Policy // 沒有規則匹配時使用
+	Rules   []Rule           // 限流規則，依序比對，第一個匹配的規則生效
+	Timeout time.Duration    // 單次限流判斷逾時，逾時視為 Redis 不可用
+}
+
+// DistributedRateLimiter 分散式限流中介層
+// 以 Redis 限流器在所有副本間共用額度，Redis 不可用時降級為各副本的記憶體限流器
+type DistributedRateLimiter struct {
+	primary  ratelimit.Limiter
+	fallback ratelimit.Limiter
+	cfg      *RateLimitConfig
+}
+
+// NewDistributedRateLimiter 創建分散式限流中介層
+func NewDistributedRateLimiter(primary ratelimit.Limiter, fallback ratelimit.Limiter, cfg *RateLimitConfig) *DistributedRateLimiter {
+	return &DistributedRateLimiter{
+		primary:  primary,
+		fallback: fallback,
+		cfg:      cfg,
+	}
+}
+
+// RateLimit 限流中介層，回應 X-RateLimit-* 與 Retry-After header
+// 依用戶或角色區分的規則需要先經過 JWTAuth 或 OptionalJWTAuth，否則視為匿名並以 IP 識別
+func (rl *DistributedRateLimiter) RateLimit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !rl.cfg.Enabled {
+			c.Next()
+			return
+		}
+
+		policy := rl.policyFor(c)
+		key := identifier(c, policy.KeyBy)
+
+		result := rl.allow(c.Request.Context(), key, policy)
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			errors.HandleError(c, errors.New(errors.ErrTooManyRequests, "too many requests"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// allow 優先使用 Redis 限流器，失敗時降級為記憶體限流器
+func (rl *DistributedRateLimiter) allow(ctx context.Context, key string, policy *ratelimit.Policy) *ratelimit.Result {
+	ctx, cancel := context.WithTimeout(ctx, rl.cfg.Timeout)
+	defer cancel()
+
+	result, err := rl.primary.Allow(ctx, key, policy)
+	if err == nil {
+		return result
+	}
+
+	logger.Warn("Distributed rate limiter unavailable, falling back to in-memory limiter",
+		zap.String("policy", policy.Name),
+		zap.Error(err),
+	)
+
+	// 記憶體限流器不會返回錯誤
+	result, _ = rl.fallback.Allow(ctx, key, policy)
+	return result
+}
+
+// policyFor 取得請求對應的限流策略
+func (rl *DistributedRateLimiter) policyFor(c *gin.Context) *ratelimit.Policy {
+	for i := range rl.cfg.Rules {
+		if rl.cfg.Rules[i].match(c) {
+			return &rl.cfg.Rules[i].Policy
+		}
+	}
+	return &rl.cfg.Default
+}
+
+// ceilSeconds 無條件進位為秒數
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,76 @@
+package request
+
+import (
+	"sync_drive_backend/internal/common/consts"
+	"sync_drive_backend/internal/common/middleware/auth"
+	"sync_drive_backend/internal/common/ratelimit"
+	"sync_drive_backend/pkg/crypto"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// HeaderAPIKey API Key header（由 API Key 驗證中介層驗證）
+	HeaderAPIKey = "X-API-Key"
+	// HeaderDeviceID 設備 ID header，僅作為 Session 的描述資訊，不用於識別
+	HeaderDeviceID = "X-Device-ID"
+)
+
+// Rule 限流規則，條件皆為空值時匹配所有請求
+// 例如管理員給予較高額度、車輛設備給予獨立額度、登入路由給予較嚴格的額度
+type Rule struct {
+	Method string      // 空值匹配所有方法
+	Path   string      // Gin 的路由樣式（例如：/api/v1/orders/:id），空值匹配所有路由
+	Role   consts.Role // 已驗證用戶的角色，空值匹配所有身分（含匿名）
+	Policy ratelimit.Policy
+}
+
+// match 是否匹配請求
+func (r *Rule) match(c *gin.Context) bool {
+	if r.Method != "" && r.Method != c.Request.Method {
+		return false
+	}
+	if r.Path != "" && r.Path != c.FullPath() {
+		return false
+	}
+	if r.Role != "" && r.Role != consts.Role(auth.GetRoleID(c)) {
+		return false
+	}
+	return true
+}
+
+// identifier 依策略取得限流識別，無法取得時退回 IP
+func identifier(c *gin.Context, strategy ratelimit.KeyStrategy) string {
+	if key := identify(c, strategy); key != "" {
+		return key
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// identify 依策略取得限流識別，無法取得時返回空字串
+// 只採用已驗證的身分，客戶端可任意更換的 header 不作為識別，避免每次請求更換 header 繞過限流
+func identify(c *gin.Context, strategy ratelimit.KeyStrategy) string {
+	switch strategy {
+	case ratelimit.KeyByIdentity:
+		for _, candidate := range []ratelimit.KeyStrategy{ratelimit.KeyByUser, ratelimit.KeyByDevice, ratelimit.KeyByAPIKey} {
+			if key := identify(c, candidate); key != "" {
+				return key
+			}
+		}
+	case ratelimit.KeyByUser:
+		if userID := auth.GetUserID(c); userID != "" {
+			return "user:" + userID
+		}
+	case ratelimit.KeyByAPIKey:
+		// 限流 key 寫入 Redis，僅保存雜湊
+		if apiKey := auth.GetAPIKey(c); apiKey != "" {
+			return "apikey:" + crypto.SHA256(apiKey)
+		}
+	case ratelimit.KeyByDevice:
+		// 每次登入的 Session 對應一台設備，以 Token 中的 Session ID 識別
+		if sessionID := auth.GetSessionID(c); sessionID != "" {
+			return "device:" + sessionID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,152 @@
+package policy
+
+import (
+	"context"
+	"slices"
+
+	"sync_drive_backend/internal/common/consts"
+	"sync_drive_backend/internal/common/util"
+	apperrors "sync_drive_backend/pkg/errors"
+)
+
+// Subject 授權主體
+type Subject struct {
+	UserID   string
+	Role     consts.Role
+	FleetIDs []string // 所屬車隊，由車隊資料提供
+}
+
+// SubjectFromContext 由 request context 中已驗證的用戶建立主體（需經過 JWTAuth），FleetIDs 由呼叫端補上
+func SubjectFromContext(ctx context.Context) *Subject {
+	return &Subject{
+		UserID: util.GetUserIDFromContext(ctx),
+		Role:   consts.Role(util.GetRoleIDFromContext(ctx)),
+	}
+}
+
+// Resource 受保護資源的授權屬性，沒有該屬性時保持空字串
+type Resource struct {
+	OwnerID string // 擁有者（例如：訂單指派的司機）
+	FleetID string // 所屬車隊
+}
+
+// Scope 主體可存取的資源範圍，各條件為 OR
+type Scope struct {
+	All      bool     // 不限範圍
+	OwnerIDs []string // 擁有者為其中之一
+	FleetIDs []string // 所屬車隊為其中之一
+}
+
+// Empty 是否無法存取任何資源
+func (s *Scope) Empty() bool {
+	return !s.All && len(s.OwnerIDs) == 0 && len(s.FleetIDs) == 0
+}
+
+// Allows 資源是否在範圍內
+func (s *Scope) Allows(resource Resource) bool {
+	if s.All {
+		return true
+	}
+	if resource.OwnerID != "" && slices.Contains(s.OwnerIDs, resource.OwnerID) {
+		return true
+	}
+	return resource.FleetID != "" && slices.Contains(s.FleetIDs, resource.FleetID)
+}
+
+// merge 合併其他規則的範圍
+func (s *Scope) merge(other *Scope) {
+	s.All = s.All || other.All
+	for _, id := range other.OwnerIDs {
+		if !slices.Contains(s.OwnerIDs, id) {
+			s.OwnerIDs = append(s.OwnerIDs, id)
+		}
+	}
+	for _, id := range other.FleetIDs {
+		if !slices.Contains(s.FleetIDs, id) {
+			s.FleetIDs = append(s.FleetIDs, id)
+		}
+	}
+}
+
+// PermissionChecker 角色權限查詢
+type PermissionChecker interface {
+	// HasPermission 角色是否具備指定權限
+	HasPermission(ctx context.Context, role consts.Role, permission consts.Permission) (bool, error)
+}
+
+// Engine 資源層級的授權
+// 先以角色權限（RBAC）判斷能否執行操作，再以該操作定義的規則決定可存取的資源範圍
+// 操作未定義規則時，具備權限即可存取全部資源
+//
+//	engine.Define(consts.PermissionOrderRead,
+//		policy.ForRoles(policy.Everything(), consts.RoleAdmin),
+//		policy.ForRoles(policy.InFleets(), consts.RoleFleetManager),
+//		policy.ForRoles(policy.Owned(), consts.RoleDriver),
+//	)
+//
+//	subject := policy.SubjectFromContext(ctx)
+//	if err := engine.Authorize(ctx, subject, consts.PermissionOrderRead, policy.Resource{OwnerID: order.DriverID, FleetID: order.FleetID}); err != nil {
+//		return nil, err
+//	}
+type Engine struct {
+	checker  PermissionChecker
+	policies map[consts.Permission][]Rule
+}
+
+// NewEngine 創建授權引擎
+func NewEngine(checker PermissionChecker) *Engine {
+	return &Engine{
+		checker:  checker,
+		policies: make(map[consts.Permission][]Rule),
+	}
+}
+
+// Define 定義操作的存取規則，多條規則的範圍取聯集；需於處理請求前完成
+func (e *Engine) Define(action consts.Permission, rules ...Rule) {
+	e.policies[action] = append(e.policies[action], rules...)
+}
+
+// Scope 取得主體執行操作時可存取的資源範圍，無權限時返回空範圍
+func (e *Engine) Scope(ctx context.Context, subject *Subject, action consts.Permission) (*Scope, error) {
+	ok, err := e.checker.HasPermission(ctx, subject.Role, action)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &Scope{}, nil
+	}
+
+	rules, defined := e.policies[action]
+	if !defined {
+		return &Scope{All: true}, nil
+	}
+
+	scope := &Scope{}
+	for _, rule := range rules {
+		if s := rule(subject); s != nil {
+			scope.merge(s)
+		}
+	}
+	return scope, nil
+}
+
+// Can 主體能否對資源執行操作
+func (e *Engine) Can(ctx context.Context, subject *Subject, action consts.Permission, resource Resource) (bool, error) {
+	scope, err := e.Scope(ctx, subject, action)
+	if err != nil {
+		return false, err
+	}
+	return scope.Allows(resource), nil
+}
+
+// Authorize 同 Can，無權限時返回 ErrForbidden 的 AppError
+func (e *Engine) Authorize(ctx context.Context, subject *Subject, action consts.Permission, resource Resource) error {
+	ok, err := e.Can(ctx, subject, action, resource)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return apperrors.New(apperrors.ErrForbidden, "access denied")
+	}
+	return nil
+}
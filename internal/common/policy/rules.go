@@ -0,0 +1,69 @@
+package policy
+
+import (
+	"slices"
+
+	"sync_drive_backend/internal/common/consts"
+)
+
+// Rule 依主體決定可存取的資源範圍，不適用時返回 nil
+type Rule func(subject *Subject) *Scope
+
+// Everything 可存取全部資源
+func Everything() Rule {
+	return func(*Subject) *Scope {
+		return &Scope{All: true}
+	}
+}
+
+// Owned 可存取自己擁有的資源
+func Owned() Rule {
+	return func(subject *Subject) *Scope {
+		if subject.UserID == "" {
+			return nil
+		}
+		return &Scope{OwnerIDs: []string{subject.UserID}}
+	}
+}
+
+// InFleets 可存取所屬車隊的資源
+func InFleets() Rule {
+	return func(subject *Subject) *Scope {
+		if len(subject.FleetIDs) == 0 {
+			return nil
+		}
+		return &Scope{FleetIDs: slices.Clone(subject.FleetIDs)}
+	}
+}
+
+// ForRoles 僅對指定角色套用規則
+func ForRoles(rule Rule, roles ...consts.Role) Rule {
+	return func(subject *Subject) *Scope {
+		if !slices.Contains(roles, subject.Role) {
+			return nil
+		}
+		return rule(subject)
+	}
+}
+
+// RegisterDefaults 訂單與車輛的預設規則
+// 管理員不限範圍；車隊管理者限所屬車隊；其他角色只能查詢自己的訂單與所屬車隊的車輛
+func RegisterDefaults(e *Engine) {
+	e.Define(consts.PermissionOrderRead,
+		ForRoles(Everything(), consts.RoleAdmin),
+		ForRoles(InFleets(), consts.RoleFleetManager),
+		Owned(),
+	)
+	e.Define(consts.PermissionOrderAssign,
+		ForRoles(Everything(), consts.RoleAdmin),
+		ForRoles(InFleets(), consts.RoleFleetManager),
+	)
+	e.Define(consts.PermissionVehicleRead,
+		ForRoles(Everything(), consts.RoleAdmin),
+		InFleets(),
+	)
+	e.Define(consts.PermissionVehicleEdit,
+		ForRoles(Everything(), consts.RoleAdmin),
+		ForRoles(InFleets(), consts.RoleFleetManager),
+	)
+}
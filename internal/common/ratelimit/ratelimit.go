@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Algorithm 限流演算法
+type Algorithm string
+
+const (
+	AlgorithmTokenBucket   Algorithm = "token_bucket"   // Token Bucket：允許短時間突發，長期平均不超過速率
+	AlgorithmSlidingWindow Algorithm = "sliding_window" // Sliding Window Counter：以前後兩個窗口加權估算，邊界平滑
+)
+
+// KeyStrategy 限流識別方式
+type KeyStrategy string
+
+const (
+	KeyByIP     KeyStrategy = "ip"     // 客戶端 IP
+	KeyByUser   KeyStrategy = "user"   // 已驗證用戶 ID（需在 JWTAuth 之後）
+	KeyByAPIKey KeyStrategy = "apikey" // 已通過驗證的 API Key（以雜湊識別）
+	KeyByDevice KeyStrategy = "device" // 已驗證 Token 的登入 Session（每台設備一個 Session）
+
+	// KeyByIdentity 依序使用用戶 ID、設備 ID、API Key，皆無時退回 IP
+	KeyByIdentity KeyStrategy = "identity"
+)
+
+// Policy 限流策略
+type Policy struct {
+	Name      string        // 策略名稱，不同策略的計數互不影響
+	Algorithm Algorithm     // 限流演算法
+	Limit     int           // 每個窗口允許的請求數（token bucket 為每個窗口補充的 token 數）
+	Window    time.Duration // 窗口大小
+	Burst     int           // token bucket 容量，0 時等於 Limit
+	KeyBy     KeyStrategy   // 識別方式，無法取得時退回 IP
+}
+
+// Capacity token bucket 容量
+func (p *Policy) Capacity() int {
+	if p.Burst > 0 {
+		return p.Burst
+	}
+	return p.Limit
+}
+
+// Validate 檢查策略設定，窗口與額度必須為正數
+func (p *Policy) Validate() error {
+	if p.Window <= 0 {
+		return fmt.Errorf("rate limit policy %q: window must be positive", p.Name)
+	}
+	if p.Limit <= 0 {
+		return fmt.Errorf("rate limit policy %q: limit must be positive", p.Name)
+	}
+	switch p.Algorithm {
+	case AlgorithmTokenBucket, AlgorithmSlidingWindow, "":
+	default:
+		return fmt.Errorf("rate limit policy %q: unsupported algorithm %q", p.Name, p.Algorithm)
+	}
+	return nil
+}
+
+// Result 限流判斷結果
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration // 額度完全恢復所需時間
+	RetryAfter time.Duration // 被拒絕時建議的重試等待時間
+}
+
+// Limiter 限流器
+type Limiter interface {
+	Allow(ctx context.Context, key string, policy *Policy) (*Result, error)
+}
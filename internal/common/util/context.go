@@ -0,0 +1,45 @@
+package util
+
+import "context"
+
+// contextKey 避免與其他套件的 context key 衝突
+type contextKey string
+
+const (
+	// ctxKeyUserID 用戶 ID 的 context key
+	ctxKeyUserID contextKey = "user_id"
+	// ctxKeyRoleID 角色 ID 的 context key
+	ctxKeyRoleID contextKey = "role_id"
+)
+
+// WithUserID 將用戶 ID 存入 context
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, ctxKeyUserID, userID)
+}
+
+// GetUserIDFromContext 從 context 取得用戶 ID，不存在時返回空字串
+func GetUserIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if userID, ok := ctx.Value(ctxKeyUserID).(string); ok {
+		return userID
+	}
+	return ""
+}
+
+// WithRoleID 將角色 ID 存入 context
+func WithRoleID(ctx context.Context, roleID string) context.Context {
+	return context.WithValue(ctx, ctxKeyRoleID, roleID)
+}
+
+// GetRoleIDFromContext 從 context 取得角色 ID，不存在時返回空字串
+func GetRoleIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if roleID, ok := ctx.Value(ctxKeyRoleID).(string); ok {
+		return roleID
+	}
+	return ""
+}
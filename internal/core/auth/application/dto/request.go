@@ -0,0 +1,118 @@
+package dto
+
+// RegisterRequest 註冊請求
+type RegisterRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=64,alphanum"`
+	Email    string `json:"email" binding:"required,email,max=255"`
+	Password string `json:"password" binding:"required,max=72"` // bcrypt 只使用前 72 bytes
+}
+
+// LoginRequest 登入請求
+type LoginRequest struct {
+	Account  string `json:"account" binding:"required"` // 用戶名稱或 Email
+	Password string `json:"password" binding:"required,max=72"`
+}
+
+// UpdateProfileRequest 更新個人資料請求
+type UpdateProfileRequest struct {
+	DisplayName string `json:"display_name" binding:"required,max=128"`
+	Phone       string `json:"phone" binding:"omitempty,max=32"`
+}
+
+// ChangePasswordRequest 變更密碼請求
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required,max=72"`
+	NewPassword string `json:"new_password" binding:"required,max=72"`
+}
+
+// RefreshRequest 換發 Token 請求
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest 登出請求
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// ClientInfo 發出請求的客戶端資訊，記錄於登入 Session
+type ClientInfo struct {
+	DeviceID  string
+	UserAgent string
+	IP        string
+	Locale    string // Accept-Language，用於選擇郵件語系
+}
+
+// ForgotPasswordRequest 忘記密碼請求
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+}
+
+// ResetPasswordRequest 重設密碼請求
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required,max=128"`
+	NewPassword string `json:"new_password" binding:"required,max=72"`
+}
+
+// VerifyEmailRequest 驗證 Email 請求
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required,max=128"`
+}
+
+// MFACodeRequest TOTP 驗證碼請求
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+// MFAEnrollRequest 登入時強制綁定 TOTP 請求
+type MFAEnrollRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required,max=128"`
+}
+
+// MFAVerifyRequest MFA 登入第二步請求
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required,max=128"`
+	Code     string `json:"code" binding:"required,max=32"` // TOTP 驗證碼或復原碼
+}
+
+// DisableMFARequest 解除 TOTP 綁定請求
+type DisableMFARequest struct {
+	Password string `json:"password" binding:"required,max=72"`
+	Code     string `json:"code" binding:"required,max=32"` // TOTP 驗證碼或復原碼
+}
+
+// OIDCCallbackRequest OIDC 登入回呼請求，IdP 導回前端後由前端轉送 code 與 state
+// Binding 不由請求內容綁定，由 Controller 從發起授權時寫入瀏覽器的 Cookie 取得
+type OIDCCallbackRequest struct {
+	Code    string `json:"code" binding:"required,max=2048"`
+	State   string `json:"state" binding:"required,max=128"`
+	Binding string `json:"-"`
+}
+
+// CreateRoleRequest 建立角色請求
+type CreateRoleRequest struct {
+	Name        string   `json:"name" binding:"required,max=32"`
+	Description string   `json:"description" binding:"max=255"`
+	Permissions []string `json:"permissions" binding:"max=100"`
+}
+
+// UpdateRoleRequest 更新角色請求
+type UpdateRoleRequest struct {
+	Description string `json:"description" binding:"max=255"`
+}
+
+// SetRolePermissionsRequest 設定角色權限請求，以清單取代角色的全部權限
+type SetRolePermissionsRequest struct {
+	Permissions []string `json:"permissions" binding:"required,max=100"`
+}
+
+// CreatePermissionRequest 建立權限請求
+type CreatePermissionRequest struct {
+	Code        string `json:"code" binding:"required,max=64"`
+	Description string `json:"description" binding:"max=255"`
+}
+
+// AssignRoleRequest 指派用戶角色請求
+type AssignRoleRequest struct {
+	Role string `json:"role" binding:"required,max=32"`
+}
@@ -0,0 +1,83 @@
+package dto
+
+import "time"
+
+// TokenResponse Token 響應
+type TokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	TokenType        string `json:"token_type"`         // 固定為 Bearer
+	ExpiresIn        int64  `json:"expires_in"`         // Access Token 剩餘秒數
+	RefreshExpiresIn int64  `json:"refresh_expires_in"` // Refresh Token 剩餘秒數
+}
+
+// SessionResponse 登入 Session 響應
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	DeviceID   string    `json:"device_id,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"` // 是否為目前請求使用的 Session
+}
+
+// UserResponse 用戶響應
+type UserResponse struct {
+	ID            string     `json:"id"`
+	Username      string     `json:"username"`
+	Email         string     `json:"email"`
+	DisplayName   string     `json:"display_name"`
+	Phone         string     `json:"phone,omitempty"`
+	Role          string     `json:"role"`
+	EmailVerified bool       `json:"email_verified"`
+	MFAEnabled    bool       `json:"mfa_enabled"`
+	LastLoginAt   *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// LoginResponse 登入響應
+// 需要 MFA 時不返回 User 與 Tokens，以 MFAToken 呼叫 /auth/mfa/verify 完成登入
+type LoginResponse struct {
+	User                  *UserResponse  `json:"user,omitempty"`
+	Tokens                *TokenResponse `json:"tokens,omitempty"`
+	MFARequired           bool           `json:"mfa_required,omitempty"`
+	MFAEnrollmentRequired bool           `json:"mfa_enrollment_required,omitempty"` // 角色強制 MFA 但尚未綁定，需先呼叫 /auth/mfa/enroll
+	MFAToken              string         `json:"mfa_token,omitempty"`
+	RecoveryCodes         []string       `json:"recovery_codes,omitempty"` // 登入時完成綁定才返回，僅顯示一次
+}
+
+// MFAEnrollmentResponse TOTP 綁定響應
+type MFAEnrollmentResponse struct {
+	Secret     string `json:"secret"`      // 供無法掃描 QR Code 時手動輸入
+	OTPAuthURI string `json:"otpauth_uri"` // QR Code 內容
+}
+
+// RecoveryCodesResponse 復原碼響應，僅顯示一次
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// OIDCAuthorizeResponse OIDC 授權網址響應，前端將瀏覽器導向此網址
+// Binding 為 state 的綁定值，由 Controller 寫入 Cookie，不在響應內容中返回
+type OIDCAuthorizeResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+	Binding          string `json:"-"`
+}
+
+// RoleResponse 角色響應
+type RoleResponse struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	BuiltIn     bool      `json:"built_in"` // 內建角色不可刪除
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PermissionResponse 權限響應
+type PermissionResponse struct {
+	Code        string    `json:"code"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,264 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"sync_drive_backend/internal/core/auth/domain/entity"
+	"sync_drive_backend/internal/core/auth/domain/repository"
+)
+
+// memUserRepository 記憶體用戶儲存庫，保存與返回副本
+type memUserRepository struct {
+	mu    sync.Mutex
+	users map[string]*entity.User
+}
+
+var _ repository.IUserRepository = (*memUserRepository)(nil)
+
+func newMemUserRepository() *memUserRepository {
+	return &memUserRepository{users: make(map[string]*entity.User)}
+}
+
+func (r *memUserRepository) Create(_ context.Context, user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.create(user)
+}
+
+func (r *memUserRepository) create(user *entity.User) error {
+	for _, u := range r.users {
+		if u.ID == user.ID || u.Username == user.Username || u.Email == user.Email {
+			return repository.ErrUserAlreadyExists
+		}
+	}
+	copied := *user
+	r.users[user.ID] = &copied
+	return nil
+}
+
+func (r *memUserRepository) FindByID(_ context.Context, id string) (*entity.User, error) {
+	return r.find(func(u *entity.User) bool { return u.ID == id })
+}
+
+func (r *memUserRepository) FindByUsername(_ context.Context, username string) (*entity.User, error) {
+	return r.find(func(u *entity.User) bool { return u.Username == username })
+}
+
+func (r *memUserRepository) FindByEmail(_ context.Context, email string) (*entity.User, error) {
+	return r.find(func(u *entity.User) bool { return u.Email == email })
+}
+
+func (r *memUserRepository) Update(_ context.Context, user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[user.ID]; !ok {
+		return repository.ErrUserNotFound
+	}
+	copied := *user
+	r.users[user.ID] = &copied
+	return nil
+}
+
+func (r *memUserRepository) find(match func(*entity.User) bool) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if match(u) {
+			copied := *u
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrUserNotFound
+}
+
+// memIdentityRepository 記憶體外部身份儲存庫
+type memIdentityRepository struct {
+	users      *memUserRepository
+	mu         sync.Mutex
+	identities map[string]*entity.UserIdentity // provider + "|" + subject
+}
+
+var _ repository.IUserIdentityRepository = (*memIdentityRepository)(nil)
+
+func newMemIdentityRepository(users *memUserRepository) *memIdentityRepository {
+	return &memIdentityRepository{users: users, identities: make(map[string]*entity.UserIdentity)}
+}
+
+func (r *memIdentityRepository) FindByProviderSubject(_ context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	identity, ok := r.identities[provider+"|"+subject]
+	if !ok {
+		return nil, repository.ErrIdentityNotFound
+	}
+	copied := *identity
+	return &copied, nil
+}
+
+func (r *memIdentityRepository) Link(_ context.Context, identity *entity.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := identity.Provider + "|" + identity.Subject
+	if _, ok := r.identities[key]; ok {
+		return repository.ErrIdentityAlreadyLinked
+	}
+	copied := *identity
+	r.identities[key] = &copied
+	return nil
+}
+
+func (r *memIdentityRepository) Provision(_ context.Context, user *entity.User, identity *entity.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := identity.Provider + "|" + identity.Subject
+	if _, ok := r.identities[key]; ok {
+		return repository.ErrIdentityAlreadyLinked
+	}
+
+	r.users.mu.Lock()
+	err := r.users.create(user)
+	r.users.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	copied := *identity
+	r.identities[key] = &copied
+	return nil
+}
+
+// byUser 用戶綁定的外部身份
+func (r *memIdentityRepository) byUser(userID string) []*entity.UserIdentity {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var identities []*entity.UserIdentity
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			identities = append(identities, identity)
+		}
+	}
+	return identities
+}
+
+// memOIDCStateRepository 記憶體 OIDC state 暫存
+type memOIDCStateRepository struct {
+	mu     sync.Mutex
+	states map[string]*entity.OIDCState
+}
+
+var _ repository.IOIDCStateRepository = (*memOIDCStateRepository)(nil)
+
+func newMemOIDCStateRepository() *memOIDCStateRepository {
+	return &memOIDCStateRepository{states: make(map[string]*entity.OIDCState)}
+}
+
+func (r *memOIDCStateRepository) Save(_ context.Context, state string, data *entity.OIDCState, _ time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *data
+	r.states[state] = &copied
+	return nil
+}
+
+func (r *memOIDCStateRepository) Consume(_ context.Context, state string) (*entity.OIDCState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, ok := r.states[state]
+	if !ok {
+		return nil, repository.ErrOIDCStateNotFound
+	}
+	delete(r.states, state)
+	return data, nil
+}
+
+// memSessionRepository 記憶體 Session 儲存庫，僅支援建立與列出
+type memSessionRepository struct {
+	mu       sync.Mutex
+	sessions map[string]*entity.Session
+}
+
+var _ repository.ISessionRepository = (*memSessionRepository)(nil)
+
+func newMemSessionRepository() *memSessionRepository {
+	return &memSessionRepository{sessions: make(map[string]*entity.Session)}
+}
+
+func (r *memSessionRepository) Create(_ context.Context, session *entity.Session, _ time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *session
+	r.sessions[session.ID] = &copied
+	return nil
+}
+
+func (r *memSessionRepository) Rotate(context.Context, *entity.Session, string, time.Duration) error {
+	return repository.ErrSessionNotFound
+}
+
+func (r *memSessionRepository) List(_ context.Context, userID string) ([]*entity.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var sessions []*entity.Session
+	for _, s := range r.sessions {
+		if s.UserID == userID {
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions, nil
+}
+
+func (r *memSessionRepository) Revoke(context.Context, string, string) error {
+	return repository.ErrSessionNotFound
+}
+
+func (r *memSessionRepository) RevokeAll(context.Context, string, time.Time, time.Duration) error {
+	return nil
+}
+
+func (r *memSessionRepository) IsAccessRevoked(context.Context, string, string, string, time.Time, time.Time) (bool, error) {
+	return false, nil
+}
+
+// memMFAChallengeRepository 記憶體 MFA Challenge 儲存庫
+type memMFAChallengeRepository struct {
+	mu         sync.Mutex
+	challenges map[string]*entity.MFAChallenge
+}
+
+var _ repository.IMFAChallengeRepository = (*memMFAChallengeRepository)(nil)
+
+func newMemMFAChallengeRepository() *memMFAChallengeRepository {
+	return &memMFAChallengeRepository{challenges: make(map[string]*entity.MFAChallenge)}
+}
+
+func (r *memMFAChallengeRepository) Create(_ context.Context, tokenHash string, challenge *entity.MFAChallenge, _ time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *challenge
+	r.challenges[tokenHash] = &copied
+	return nil
+}
+
+func (r *memMFAChallengeRepository) Get(_ context.Context, tokenHash string) (*entity.MFAChallenge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	challenge, ok := r.challenges[tokenHash]
+	if !ok {
+		return nil, repository.ErrMFAChallengeNotFound
+	}
+	copied := *challenge
+	return &copied, nil
+}
+
+func (r *memMFAChallengeRepository) Attempt(ctx context.Context, tokenHash string, _ int) (*entity.MFAChallenge, error) {
+	return r.Get(ctx, tokenHash)
+}
+
+func (r *memMFAChallengeRepository) Delete(_ context.Context, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.challenges, tokenHash)
+	return nil
+}
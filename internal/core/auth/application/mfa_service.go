@@ -0,0 +1,392 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"sync_drive_backend/internal/common/consts"
+	"sync_drive_backend/internal/core/auth/application/dto"
+	"sync_drive_backend/internal/core/auth/domain/entity"
+	"sync_drive_backend/internal/core/auth/domain/repository"
+	"sync_drive_backend/internal/core/auth/domain/service"
+	"sync_drive_backend/pkg/crypto"
+	apperrors "sync_drive_backend/pkg/errors"
+	"sync_drive_backend/pkg/logger"
+	"sync_drive_backend/pkg/totp"
+
+	"go.uber.org/zap"
+)
+
+// totpSkew 允許前後一個時間步（30 秒）的時鐘誤差
+const totpSkew = 1
+
+// recoveryCodeEncoding 復原碼編碼（小寫 base32，避免大小寫混淆）
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// MFAConfig 多因素驗證配置
+type MFAConfig struct {
+	Issuer            string        // 驗證器 App 顯示的服務名稱
+	EncryptionKey     []byte        // TOTP 金鑰加密用的 AES-256 金鑰
+	RequiredRoles     []consts.Role // 強制 MFA 的角色，未綁定者登入時須先完成綁定
+	ChallengeTTL      time.Duration // MFA Challenge 有效時間
+	MaxAttempts       int           // 每個 Challenge 最多驗證次數
+	RecoveryCodeCount int           // 復原碼數量
+}
+
+// MFAService TOTP 多因素驗證
+// 密碼驗證通過後若需要 MFA，返回短效的 Challenge Token，以 TOTP 驗證碼或復原碼完成登入
+type MFAService struct {
+	cfg           *MFAConfig
+	userRepo      repository.IUserRepository
+	challengeRepo repository.IMFAChallengeRepository
+	domainSvc     *service.AuthDomainService
+	tokenService  *TokenService
+	loginGuard    *LoginGuard
+}
+
+// NewMFAService 創建 MFA 服務
+func NewMFAService(
+	cfg *MFAConfig,
+	userRepo repository.IUserRepository,
+	challengeRepo repository.IMFAChallengeRepository,
+	domainSvc *service.AuthDomainService,
+	tokenService *TokenService,
+	loginGuard *LoginGuard,
+) *MFAService {
+	return &MFAService{
+		cfg:           cfg,
+		userRepo:      userRepo,
+		challengeRepo: challengeRepo,
+		domainSvc:     domainSvc,
+		tokenService:  tokenService,
+		loginGuard:    loginGuard,
+	}
+}
+
+// Required 登入是否需要 MFA
+func (s *MFAService) Required(user *entity.User) bool {
+	return user.MFAEnabled || s.roleRequired(user.Role)
+}
+
+// Challenge 建立 MFA Challenge，作為密碼驗證通過後的登入響應
+func (s *MFAService) Challenge(ctx context.Context, user *entity.User) (*dto.LoginResponse, error) {
+	token, err := newRandomToken()
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalError, "failed to generate token", err)
+	}
+
+	challenge := &entity.MFAChallenge{
+		UserID:     user.ID,
+		Enrollment: !user.MFAEnabled,
+	}
+	if err := s.challengeRepo.Create(ctx, crypto.SHA256(token), challenge, s.cfg.ChallengeTTL); err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalError, "failed to save mfa challenge", err)
+	}
+
+	return &dto.LoginResponse{
+		MFARequired:           true,
+		MFAEnrollmentRequired: challenge.Enrollment,
+		MFAToken:              token,
+	}, nil
+}
+
+// Verify MFA 登入第二步，驗證成功後簽發 Token
+// 強制綁定的 Challenge 以驗證碼確認綁定，並於響應中返回復原碼
+func (s *MFAService) Verify(ctx context.Context, req *dto.MFAVerifyRequest, client *dto.ClientInfo) (*dto.LoginResponse, error) {
+	tokenHash := crypto.SHA256(req.MFAToken)
+
+	challenge, err := s.challengeRepo.Attempt(ctx, tokenHash, s.cfg.MaxAttempts)
+	if errors.Is(err, repository.ErrMFAChallengeNotFound) {
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "mfa challenge expired, please login again")
+	}
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalError, "failed to load mfa challenge", err)
+	}
+
+	user, err := s.activeUser(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loginGuard.Check(ctx, user, "", client.IP); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var recoveryCodes []string
+	if challenge.Enrollment && !user.MFAEnabled {
+		if user.MFASecret == "" {
+			return nil, apperrors.New(apperrors.ErrInvalidParams, "mfa enrollment not started")
+		}
+		if !s.verifyTOTP(user, req.Code, now) {
+			s.loginGuard.Failed(ctx, user, "", client.IP)
+			return nil, apperrors.New(apperrors.ErrUnauthorized, "invalid mfa code")
+		}
+
+		var hashes []string
+		recoveryCodes, hashes, err = s.newRecoveryCodes()
+		if err != nil {
+			return nil, err
+		}
+		user.EnableMFA(hashes)
+	} else if !s.verifyCode(user, req.Code, now) {
+		logger.Warn("Invalid mfa code", zap.String("user_id", user.ID), zap.String("ip", client.IP))
+		s.loginGuard.Failed(ctx, user, "", client.IP)
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "invalid mfa code")
+	}
+
+	// 同時驗證成功時版本衝突，驗證碼與復原碼只會被使用一次
+	user.RecordLogin(now)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	s.loginGuard.Succeeded(ctx, user)
+	if err := s.challengeRepo.Delete(ctx, tokenHash); err != nil {
+		logger.Warn("Failed to delete mfa challenge", zap.String("user_id", user.ID), zap.Error(err))
+	}
+
+	tokens, err := s.tokenService.Issue(ctx, user.ID, user.Username, user.Role.String(), client)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.LoginResponse{
+		User:          toUserResponse(user),
+		Tokens:        tokens,
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// EnrollWithChallenge 強制 MFA 的角色登入時綁定 TOTP，以 MFA Token 代替 Access Token
+func (s *MFAService) EnrollWithChallenge(ctx context.Context, req *dto.MFAEnrollRequest) (*dto.MFAEnrollmentResponse, error) {
+	challenge, err := s.challengeRepo.Get(ctx, crypto.SHA256(req.MFAToken))
+	if errors.Is(err, repository.ErrMFAChallengeNotFound) {
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "mfa challenge expired, please login again")
+	}
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalError, "failed to load mfa challenge", err)
+	}
+	if !challenge.Enrollment {
+		return nil, apperrors.New(apperrors.ErrInvalidParams, "mfa already enabled")
+	}
+
+	user, err := s.activeUser(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return s.beginEnrollment(ctx, user)
+}
+
+// BeginEnrollment 已登入用戶開始綁定 TOTP，需以 ConfirmEnrollment 確認後才啟用
+func (s *MFAService) BeginEnrollment(ctx context.Context, userID string) (*dto.MFAEnrollmentResponse, error) {
+	user, err := s.activeUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.beginEnrollment(ctx, user)
+}
+
+// ConfirmEnrollment 以驗證碼確認綁定並產生復原碼
+func (s *MFAService) ConfirmEnrollment(ctx context.Context, userID string, req *dto.MFACodeRequest) (*dto.RecoveryCodesResponse, error) {
+	user, err := s.activeUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, apperrors.New(apperrors.ErrInvalidParams, "mfa already enabled")
+	}
+	if user.MFASecret == "" {
+		return nil, apperrors.New(apperrors.ErrInvalidParams, "mfa enrollment not started")
+	}
+	if !s.verifyTOTP(user, req.Code, time.Now()) {
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "invalid mfa code")
+	}
+
+	codes, hashes, err := s.newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	user.EnableMFA(hashes)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	logger.Info("MFA enabled", zap.String("user_id", user.ID))
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// RegenerateRecoveryCodes 重新產生復原碼，舊的全部失效
+func (s *MFAService) RegenerateRecoveryCodes(ctx context.Context, userID string, req *dto.MFACodeRequest, client *dto.ClientInfo) (*dto.RecoveryCodesResponse, error) {
+	user, err := s.enabledUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loginGuard.Check(ctx, user, "", client.IP); err != nil {
+		return nil, err
+	}
+	if !s.verifyTOTP(user, req.Code, time.Now()) {
+		s.loginGuard.Failed(ctx, user, "", client.IP)
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "invalid mfa code")
+	}
+	s.loginGuard.Succeeded(ctx, user)
+
+	codes, hashes, err := s.newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	user.ReplaceRecoveryCodes(hashes)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable 解除 TOTP 綁定，需再次驗證密碼與驗證碼；強制 MFA 的角色不可解除
+// 密碼與驗證碼的驗證與登入共用失敗計數
+func (s *MFAService) Disable(ctx context.Context, userID string, req *dto.DisableMFARequest, client *dto.ClientInfo) error {
+	user, err := s.enabledUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if s.roleRequired(user.Role) {
+		return apperrors.New(apperrors.ErrInvalidParams, "mfa is required for role "+user.Role.String())
+	}
+	if err := s.loginGuard.Check(ctx, user, "", client.IP); err != nil {
+		return err
+	}
+	if !s.domainSvc.VerifyPassword(user, req.Password) {
+		s.loginGuard.Failed(ctx, user, "", client.IP)
+		return apperrors.New(apperrors.ErrUnauthorized, "invalid password")
+	}
+	if !s.verifyCode(user, req.Code, time.Now()) {
+		s.loginGuard.Failed(ctx, user, "", client.IP)
+		return apperrors.New(apperrors.ErrUnauthorized, "invalid mfa code")
+	}
+	s.loginGuard.Succeeded(ctx, user)
+
+	user.DisableMFA()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	logger.Info("MFA disabled", zap.String("user_id", user.ID))
+	return nil
+}
+
+// beginEnrollment 產生新的 TOTP 金鑰，覆蓋尚未確認的金鑰
+func (s *MFAService) beginEnrollment(ctx context.Context, user *entity.User) (*dto.MFAEnrollmentResponse, error) {
+	if user.MFAEnabled {
+		return nil, apperrors.New(apperrors.ErrInvalidParams, "mfa already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalError, "failed to generate totp secret", err)
+	}
+	encrypted, err := crypto.Encrypt(s.cfg.EncryptionKey, secret)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalError, "failed to encrypt totp secret", err)
+	}
+
+	user.BeginMFAEnrollment(encrypted)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &dto.MFAEnrollmentResponse{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(s.cfg.Issuer, user.Email, secret),
+	}, nil
+}
+
+// verifyCode 驗證 TOTP 驗證碼或復原碼
+func (s *MFAService) verifyCode(user *entity.User, code string, now time.Time) bool {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		return s.verifyTOTP(user, code, now)
+	}
+	if user.UseRecoveryCode(crypto.SHA256(normalizeRecoveryCode(code))) {
+		logger.Info("Recovery code used",
+			zap.String("user_id", user.ID),
+			zap.Int("remaining", len(user.RecoveryCodes)),
+		)
+		return true
+	}
+	return false
+}
+
+// verifyTOTP 驗證 TOTP 驗證碼，同一時間步的驗證碼只能使用一次
+func (s *MFAService) verifyTOTP(user *entity.User, code string, now time.Time) bool {
+	secret, err := crypto.Decrypt(s.cfg.EncryptionKey, user.MFASecret)
+	if err != nil {
+		logger.Error("Failed to decrypt totp secret", zap.String("user_id", user.ID), zap.Error(err))
+		return false
+	}
+
+	counter, ok := totp.Validate(secret, code, now, totpSkew)
+	if !ok {
+		return false
+	}
+	return user.UseTOTPCounter(counter)
+}
+
+// newRecoveryCodes 產生復原碼，返回明文（顯示給用戶）與雜湊（保存）
+func (s *MFAService) newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, s.cfg.RecoveryCodeCount)
+	hashes := make([]string, 0, s.cfg.RecoveryCodeCount)
+
+	for i := 0; i < s.cfg.RecoveryCodeCount; i++ {
+		b := make([]byte, 6)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, apperrors.Wrap(apperrors.ErrInternalError, "failed to generate recovery code", err)
+		}
+		raw := recoveryCodeEncoding.EncodeToString(b)
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, crypto.SHA256(raw))
+	}
+	return codes, hashes, nil
+}
+
+// activeUser 取得可登入的用戶
+func (s *MFAService) activeUser(ctx context.Context, userID string) (*entity.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "account has been disabled")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive() {
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "account has been disabled")
+	}
+	return user, nil
+}
+
+// enabledUser 取得已綁定 TOTP 的用戶
+func (s *MFAService) enabledUser(ctx context.Context, userID string) (*entity.User, error) {
+	user, err := s.activeUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.MFAEnabled {
+		return nil, apperrors.New(apperrors.ErrInvalidParams, "mfa not enabled")
+	}
+	return user, nil
+}
+
+// roleRequired 角色是否強制 MFA
+func (s *MFAService) roleRequired(role consts.Role) bool {
+	for _, r := range s.cfg.RequiredRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeRecoveryCode 忽略大小寫、空白與分隔符號
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,368 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"sync_drive_backend/internal/common/consts"
+	"sync_drive_backend/internal/common/util"
+	"sync_drive_backend/internal/core/auth/application/dto"
+	"sync_drive_backend/internal/core/auth/domain/entity"
+	"sync_drive_backend/internal/core/auth/domain/repository"
+	apperrors "sync_drive_backend/pkg/errors"
+	"sync_drive_backend/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// PermissionService 角色與權限管理，並提供路由的權限檢查
+// 角色權限由資料庫管理，Access Token 只記錄角色，權限異動即時生效，用戶角色變更則需重新登入
+type PermissionService struct {
+	roleRepo     repository.IRoleRepository
+	userRepo     repository.IUserRepository
+	tokenService *TokenService
+}
+
+// NewPermissionService 創建權限服務
+func NewPermissionService(roleRepo repository.IRoleRepository, userRepo repository.IUserRepository, tokenService *TokenService) *PermissionService {
+	return &PermissionService{
+		roleRepo:     roleRepo,
+		userRepo:     userRepo,
+		tokenService: tokenService,
+	}
+}
+
+// HasPermission 角色是否具備指定權限，角色不存在時視為無權限
+func (s *PermissionService) HasPermission(ctx context.Context, role consts.Role, permission consts.Permission) (bool, error) {
+	r, err := s.roleRepo.FindRole(ctx, role)
+	if errors.Is(err, repository.ErrRoleNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, apperrors.Wrap(apperrors.ErrInternalError, "failed to check permission", err)
+	}
+	return r.HasPermission(permission), nil
+}
+
+// ListRoles 列出全部角色
+func (s *PermissionService) ListRoles(ctx context.Context) ([]*dto.RoleResponse, error) {
+	roles, err := s.roleRepo.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*dto.RoleResponse, 0, len(roles))
+	for _, role := range roles {
+		resp = append(resp, toRoleResponse(role))
+	}
+	return resp, nil
+}
+
+// GetRole 查詢角色
+func (s *PermissionService) GetRole(ctx context.Context, name string) (*dto.RoleResponse, error) {
+	role, err := s.findRole(ctx, consts.Role(name))
+	if err != nil {
+		return nil, err
+	}
+	return toRoleResponse(role), nil
+}
+
+// CreateRole 建立自訂角色
+func (s *PermissionService) CreateRole(ctx context.Context, req *dto.CreateRoleRequest) (*dto.RoleResponse, error) {
+	name := consts.Role(req.Name)
+	if !entity.ValidRoleName(name) {
+		return nil, apperrors.New(apperrors.ErrInvalidParams, "role name must be 2-32 lowercase letters, digits or underscores")
+	}
+	permissions, err := parsePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeGrant(ctx, permissions); err != nil {
+		return nil, err
+	}
+
+	role := entity.NewRole(name, req.Description, permissions)
+	if err := s.roleRepo.CreateRole(ctx, role); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRoleAlreadyExists):
+			return nil, apperrors.New(apperrors.ErrAlreadyExists, "role already exists")
+		case errors.Is(err, repository.ErrPermissionNotFound):
+			return nil, apperrors.New(apperrors.ErrInvalidParams, "permission not found")
+		}
+		return nil, err
+	}
+
+	logger.Info("Security event: role created",
+		zap.String("event", "auth.role_created"),
+		zap.String("role", role.Name.String()),
+		zap.Stringers("permissions", role.Permissions),
+		zap.String("operator", util.GetUserIDFromContext(ctx)),
+	)
+	return toRoleResponse(role), nil
+}
+
+// UpdateRole 更新角色說明
+func (s *PermissionService) UpdateRole(ctx context.Context, name string, req *dto.UpdateRoleRequest) (*dto.RoleResponse, error) {
+	role, err := s.findRole(ctx, consts.Role(name))
+	if err != nil {
+		return nil, err
+	}
+
+	role.Description = req.Description
+	if err := s.roleRepo.UpdateRole(ctx, role); err != nil {
+		if errors.Is(err, repository.ErrRoleNotFound) {
+			return nil, apperrors.New(apperrors.ErrNotFound, "role not found")
+		}
+		return nil, err
+	}
+	return s.GetRole(ctx, name)
+}
+
+// DeleteRole 刪除自訂角色，內建角色與仍有用戶使用的角色不可刪除
+func (s *PermissionService) DeleteRole(ctx context.Context, name string) error {
+	role, err := s.findRole(ctx, consts.Role(name))
+	if err != nil {
+		return err
+	}
+	if role.BuiltIn {
+		return apperrors.New(apperrors.ErrInvalidParams, "built-in role cannot be deleted")
+	}
+
+	if err := s.roleRepo.DeleteRole(ctx, role.Name); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRoleNotFound):
+			return apperrors.New(apperrors.ErrNotFound, "role not found")
+		case errors.Is(err, repository.ErrRoleInUse):
+			return apperrors.New(apperrors.ErrInvalidParams, "role is assigned to users")
+		}
+		return err
+	}
+
+	logger.Info("Security event: role deleted",
+		zap.String("event", "auth.role_deleted"),
+		zap.String("role", role.Name.String()),
+		zap.String("operator", util.GetUserIDFromContext(ctx)),
+	)
+	return nil
+}
+
+// SetRolePermissions 以指定權限取代角色的全部權限，立即對已登入的用戶生效
+// 操作者的角色須涵蓋該角色目前與指定的全部權限
+func (s *PermissionService) SetRolePermissions(ctx context.Context, name string, req *dto.SetRolePermissionsRequest) (*dto.RoleResponse, error) {
+	permissions, err := parsePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	// 避免管理員移除自己的全部權限後無法再管理角色
+	role := consts.Role(name)
+	if role == consts.RoleAdmin && !slices.Contains(permissions, consts.PermissionAll) {
+		return nil, apperrors.New(apperrors.ErrInvalidParams, "admin role must keep the * permission")
+	}
+
+	current, err := s.findRole(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeGrant(ctx, slices.Concat(current.Permissions, permissions)); err != nil {
+		return nil, err
+	}
+
+	if err := s.roleRepo.SetRolePermissions(ctx, role, permissions); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRoleNotFound):
+			return nil, apperrors.New(apperrors.ErrNotFound, "role not found")
+		case errors.Is(err, repository.ErrPermissionNotFound):
+			return nil, apperrors.New(apperrors.ErrInvalidParams, "permission not found")
+		}
+		return nil, err
+	}
+
+	logger.Info("Security event: role permissions changed",
+		zap.String("event", "auth.role_permissions_changed"),
+		zap.String("role", role.String()),
+		zap.Stringers("permissions", permissions),
+		zap.String("operator", util.GetUserIDFromContext(ctx)),
+	)
+	return s.GetRole(ctx, name)
+}
+
+// ListPermissions 列出全部權限
+func (s *PermissionService) ListPermissions(ctx context.Context) ([]*dto.PermissionResponse, error) {
+	permissions, err := s.roleRepo.ListPermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*dto.PermissionResponse, 0, len(permissions))
+	for _, p := range permissions {
+		resp = append(resp, toPermissionResponse(p))
+	}
+	return resp, nil
+}
+
+// CreatePermission 建立權限
+func (s *PermissionService) CreatePermission(ctx context.Context, req *dto.CreatePermissionRequest) (*dto.PermissionResponse, error) {
+	code := consts.Permission(req.Code)
+	if !code.IsValid() {
+		return nil, apperrors.New(apperrors.ErrInvalidParams, "permission code must be in the form resource:action")
+	}
+
+	permission := &entity.Permission{Code: code, Description: req.Description}
+	if err := s.roleRepo.CreatePermission(ctx, permission); err != nil {
+		if errors.Is(err, repository.ErrPermissionAlreadyExists) {
+			return nil, apperrors.New(apperrors.ErrAlreadyExists, "permission already exists")
+		}
+		return nil, err
+	}
+	return toPermissionResponse(permission), nil
+}
+
+// DeletePermission 刪除權限並自所有角色移除
+func (s *PermissionService) DeletePermission(ctx context.Context, code string) error {
+	if consts.Permission(code) == consts.PermissionAll {
+		return apperrors.New(apperrors.ErrInvalidParams, "the * permission cannot be deleted")
+	}
+
+	if err := s.roleRepo.DeletePermission(ctx, consts.Permission(code)); err != nil {
+		if errors.Is(err, repository.ErrPermissionNotFound) {
+			return apperrors.New(apperrors.ErrNotFound, "permission not found")
+		}
+		return err
+	}
+
+	logger.Info("Security event: permission deleted",
+		zap.String("event", "auth.permission_deleted"),
+		zap.String("permission", code),
+		zap.String("operator", util.GetUserIDFromContext(ctx)),
+	)
+	return nil
+}
+
+// AssignUserRole 指派用戶角色
+// 不可變更自己的角色，操作者的角色須涵蓋用戶目前與新角色的全部權限
+// Access Token 記錄角色，變更前撤銷用戶的全部 Session，以新角色重新登入
+func (s *PermissionService) AssignUserRole(ctx context.Context, userID string, req *dto.AssignRoleRequest) (*dto.UserResponse, error) {
+	if userID == util.GetUserIDFromContext(ctx) {
+		return nil, apperrors.New(apperrors.ErrForbidden, "cannot change your own role")
+	}
+
+	role, err := s.findRole(ctx, consts.Role(req.Role))
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil, apperrors.New(apperrors.ErrNotFound, "user not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	previous := user.Role
+	if previous == role.Name {
+		return toUserResponse(user), nil
+	}
+
+	permissions := role.Permissions
+	if current, err := s.roleRepo.FindRole(ctx, previous); err == nil {
+		permissions = slices.Concat(current.Permissions, permissions)
+	} else if !errors.Is(err, repository.ErrRoleNotFound) {
+		return nil, err
+	}
+	if err := s.authorizeGrant(ctx, permissions); err != nil {
+		return nil, err
+	}
+
+	// 先撤銷 Session 再保存角色，撤銷失敗時不變更角色，避免降級後舊 Token 仍保有原角色
+	if err := s.tokenService.RevokeAll(ctx, user.ID); err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalError, "failed to revoke sessions", err)
+	}
+
+	user.AssignRole(role.Name)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Security event: user role changed",
+		zap.String("event", "auth.user_role_changed"),
+		zap.String("user_id", user.ID),
+		zap.String("from", previous.String()),
+		zap.String("to", user.Role.String()),
+		zap.String("operator", util.GetUserIDFromContext(ctx)),
+	)
+	return toUserResponse(user), nil
+}
+
+// authorizeGrant 檢查操作者的角色涵蓋全部權限，避免授予自己未具備的權限
+func (s *PermissionService) authorizeGrant(ctx context.Context, permissions []consts.Permission) error {
+	operator, err := s.roleRepo.FindRole(ctx, consts.Role(util.GetRoleIDFromContext(ctx)))
+	if errors.Is(err, repository.ErrRoleNotFound) {
+		return apperrors.New(apperrors.ErrForbidden, "insufficient permissions")
+	}
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalError, "failed to load operator role", err)
+	}
+
+	for _, p := range permissions {
+		if !operator.HasPermission(p) {
+			return apperrors.New(apperrors.ErrForbidden, "cannot grant permission not held: "+p.String())
+		}
+	}
+	return nil
+}
+
+// findRole 查詢角色，不存在時返回 ErrNotFound
+func (s *PermissionService) findRole(ctx context.Context, name consts.Role) (*entity.Role, error) {
+	role, err := s.roleRepo.FindRole(ctx, name)
+	if errors.Is(err, repository.ErrRoleNotFound) {
+		return nil, apperrors.New(apperrors.ErrNotFound, "role not found")
+	}
+	return role, err
+}
+
+// parsePermissions 驗證權限代碼格式並去除重複
+func parsePermissions(codes []string) ([]consts.Permission, error) {
+	permissions := make([]consts.Permission, 0, len(codes))
+	seen := make(map[consts.Permission]struct{}, len(codes))
+	for _, code := range codes {
+		p := consts.Permission(code)
+		if !p.IsValid() {
+			return nil, apperrors.New(apperrors.ErrInvalidParams, "invalid permission: "+code)
+		}
+		if _, ok := seen[p]; ok {
+			continue
+		}
+		seen[p] = struct{}{}
+		permissions = append(permissions, p)
+	}
+	return permissions, nil
+}
+
+// toRoleResponse 轉換為角色響應
+func toRoleResponse(role *entity.Role) *dto.RoleResponse {
+	permissions := make([]string, 0, len(role.Permissions))
+	for _, p := range role.Permissions {
+		permissions = append(permissions, p.String())
+	}
+
+	return &dto.RoleResponse{
+		Name:        role.Name.String(),
+		Description: role.Description,
+		BuiltIn:     role.BuiltIn,
+		Permissions: permissions,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+	}
+}
+
+// toPermissionResponse 轉換為權限響應
+func toPermissionResponse(permission *entity.Permission) *dto.PermissionResponse {
+	return &dto.PermissionResponse{
+		Code:        permission.Code.String(),
+		Description: permission.Description,
+		CreatedAt:   permission.CreatedAt,
+	}
+}
@@ -0,0 +1,221 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"sync_drive_backend/internal/core/auth/application/dto"
+	"sync_drive_backend/internal/core/auth/domain/entity"
+	"sync_drive_backend/internal/core/auth/domain/repository"
+	"sync_drive_backend/internal/core/auth/domain/service"
+	apperrors "sync_drive_backend/pkg/errors"
+	"sync_drive_backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// AuthService 註冊、登入與個人資料
+type AuthService struct {
+	userRepo            repository.IUserRepository
+	domainSvc           *service.AuthDomainService
+	tokenService        *TokenService
+	verificationService *VerificationService
+	mfaService          *MFAService
+	loginGuard          *LoginGuard
+}
+
+// NewAuthService 創建 Auth 服務
+func NewAuthService(
+	userRepo repository.IUserRepository,
+	domainSvc *service.AuthDomainService,
+	tokenService *TokenService,
+	verificationService *VerificationService,
+	mfaService *MFAService,
+	loginGuard *LoginGuard,
+) *AuthService {
+	return &AuthService{
+		userRepo:            userRepo,
+		domainSvc:           domainSvc,
+		tokenService:        tokenService,
+		verificationService: verificationService,
+		mfaService:          mfaService,
+		loginGuard:          loginGuard,
+	}
+}
+
+// Register 註冊並登入
+func (s *AuthService) Register(ctx context.Context, req *dto.RegisterRequest, client *dto.ClientInfo) (*dto.LoginResponse, error) {
+	passwordHash, err := s.domainSvc.HashPassword(req.Password)
+	if err != nil {
+		return nil, toPasswordError(err)
+	}
+
+	user := entity.NewUser(uuid.NewString(), req.Username, normalizeEmail(req.Email), passwordHash, time.Now())
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrUserAlreadyExists) {
+			return nil, apperrors.New(apperrors.ErrAlreadyExists, "username or email already registered")
+		}
+		return nil, err
+	}
+
+	// 驗證信寄送失敗不影響註冊，用戶可登入後重新寄送
+	if err := s.verificationService.SendEmailVerification(ctx, user.ID, client.Locale); err != nil {
+		logger.Warn("Failed to send verification email", zap.String("user_id", user.ID), zap.Error(err))
+	}
+
+	if s.mfaService.Required(user) {
+		return s.mfaService.Challenge(ctx, user)
+	}
+	return s.login(ctx, user, client)
+}
+
+// Login 以用戶名稱或 Email 登入
+// 帳號不存在與密碼錯誤返回相同的錯誤，避免探測帳號；連續失敗時漸進延遲並暫時鎖定；需要 MFA 時返回 Challenge
+func (s *AuthService) Login(ctx context.Context, req *dto.LoginRequest, client *dto.ClientInfo) (*dto.LoginResponse, error) {
+	user, err := s.findByAccount(ctx, req.Account)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, err
+	}
+
+	if err := s.loginGuard.Check(ctx, user, req.Account, client.IP); err != nil {
+		return nil, err
+	}
+	if !s.domainSvc.VerifyPassword(user, req.Password) {
+		s.loginGuard.Failed(ctx, user, req.Account, client.IP)
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "invalid account or password")
+	}
+	if !user.IsActive() {
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "account has been disabled")
+	}
+
+	if s.mfaService.Required(user) {
+		return s.mfaService.Challenge(ctx, user)
+	}
+
+	s.loginGuard.Succeeded(ctx, user)
+	user.RecordLogin(time.Now())
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		// 登入時間僅供參考，更新失敗（例如同時登入造成版本衝突）不影響登入
+		logger.Warn("Failed to record login time", zap.String("user_id", user.ID), zap.Error(err))
+	}
+
+	return s.login(ctx, user, client)
+}
+
+// GetProfile 取得個人資料
+func (s *AuthService) GetProfile(ctx context.Context, userID string) (*dto.UserResponse, error) {
+	user, err := s.findByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toUserResponse(user), nil
+}
+
+// UpdateProfile 更新個人資料
+func (s *AuthService) UpdateProfile(ctx context.Context, userID string, req *dto.UpdateProfileRequest) (*dto.UserResponse, error) {
+	user, err := s.findByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.UpdateProfile(req.DisplayName, req.Phone)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return toUserResponse(user), nil
+}
+
+// ChangePassword 變更密碼
+// 撤銷所有已簽發的 Token（其他裝置需重新登入），並為目前的裝置簽發新的 Token
+func (s *AuthService) ChangePassword(ctx context.Context, userID string, req *dto.ChangePasswordRequest, client *dto.ClientInfo) (*dto.TokenResponse, error) {
+	user, err := s.findByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 舊密碼驗證與登入共用失敗計數，避免以已登入的 Session 暴力猜測密碼
+	if err := s.loginGuard.Check(ctx, user, "", client.IP); err != nil {
+		return nil, err
+	}
+	if !s.domainSvc.VerifyPassword(user, req.OldPassword) {
+		s.loginGuard.Failed(ctx, user, "", client.IP)
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "invalid password")
+	}
+	s.loginGuard.Succeeded(ctx, user)
+
+	passwordHash, err := s.domainSvc.HashPassword(req.NewPassword)
+	if err != nil {
+		return nil, toPasswordError(err)
+	}
+
+	user.ChangePassword(passwordHash, time.Now())
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.tokenService.RevokeAll(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	return s.tokenService.Issue(ctx, user.ID, user.Username, user.Role.String(), client)
+}
+
+// login 簽發 Token
+func (s *AuthService) login(ctx context.Context, user *entity.User, client *dto.ClientInfo) (*dto.LoginResponse, error) {
+	tokens, err := s.tokenService.Issue(ctx, user.ID, user.Username, user.Role.String(), client)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.LoginResponse{
+		User:   toUserResponse(user),
+		Tokens: tokens,
+	}, nil
+}
+
+// findByAccount 依用戶名稱或 Email 查詢
+func (s *AuthService) findByAccount(ctx context.Context, account string) (*entity.User, error) {
+	if strings.Contains(account, "@") {
+		return s.userRepo.FindByEmail(ctx, normalizeEmail(account))
+	}
+	return s.userRepo.FindByUsername(ctx, account)
+}
+
+// findByID 依 ID 查詢，不存在時返回 ErrNotFound
+func (s *AuthService) findByID(ctx context.Context, userID string) (*entity.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil, apperrors.New(apperrors.ErrNotFound, "user not found")
+	}
+	return user, err
+}
+
+// normalizeEmail Email 不分大小寫
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// toPasswordError 轉換密碼強度錯誤
+func toPasswordError(err error) error {
+	if errors.Is(err, service.ErrWeakPassword) {
+		return apperrors.Wrap(apperrors.ErrInvalidParams, err.Error(), err)
+	}
+	return apperrors.Wrap(apperrors.ErrInternalError, "failed to hash password", err)
+}
+
+// toUserResponse 轉換為響應
+func toUserResponse(user *entity.User) *dto.UserResponse {
+	return &dto.UserResponse{
+		ID:            user.ID,
+		Username:      user.Username,
+		Email:         user.Email,
+		DisplayName:   user.DisplayName,
+		Phone:         user.Phone,
+		Role:          user.Role.String(),
+		EmailVerified: user.EmailVerified,
+		MFAEnabled:    user.MFAEnabled,
+		LastLoginAt:   user.LastLoginAt,
+		CreatedAt:     user.CreatedAt,
+	}
+}
@@ -0,0 +1,337 @@
+package application
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+	"unicode"
+
+	"sync_drive_backend/internal/common/consts"
+	"sync_drive_backend/internal/core/auth/application/dto"
+	"sync_drive_backend/internal/core/auth/domain/entity"
+	"sync_drive_backend/internal/core/auth/domain/repository"
+	"sync_drive_backend/pkg/crypto"
+	apperrors "sync_drive_backend/pkg/errors"
+	"sync_drive_backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// IdentityProvider 外部身份提供者（OIDC）
+type IdentityProvider interface {
+	// AuthCodeURL 產生授權網址
+	AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error)
+	// Exchange 以授權碼換取並驗證 ID Token
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*entity.ExternalProfile, error)
+}
+
+// RoleMapping IdP 群組對應的角色
+type RoleMapping struct {
+	Group string
+	Role  consts.Role
+}
+
+// SSOConfig 單一登入配置
+type SSOConfig struct {
+	Enabled       bool
+	StateTTL      time.Duration // 授權請求有效時間
+	AutoProvision bool          // 未綁定且無相同 Email 的用戶時自動建立
+	RoleMappings  []RoleMapping // 依序比對，第一個符合的群組決定角色；未設定時不變更角色
+	DefaultRole   consts.Role   // 沒有任何群組符合時的角色
+}
+
+// syncsRole 角色是否由 IdP 群組管理，只有對應表中的角色與預設角色會被同步，其他角色由管理員指派後保持不變
+func (c *SSOConfig) syncsRole(role consts.Role) bool {
+	if len(c.RoleMappings) == 0 {
+		return false
+	}
+	if role == c.DefaultRole {
+		return true
+	}
+	for _, mapping := range c.RoleMappings {
+		if mapping.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// SSOService OIDC 單一登入
+// 以 IdP 的 issuer 與 sub 識別用戶；首次登入時依 IdP 已驗證的 Email 綁定既有用戶，或自動建立用戶
+// 每次登入依 IdP 群組同步由群組管理的角色，之後簽發本系統的 Token
+type SSOService struct {
+	cfg          *SSOConfig
+	provider     IdentityProvider
+	userRepo     repository.IUserRepository
+	identityRepo repository.IUserIdentityRepository
+	stateRepo    repository.IOIDCStateRepository
+	tokenService *TokenService
+	mfaService   *MFAService
+}
+
+// NewSSOService 創建單一登入服務
+func NewSSOService(
+	cfg *SSOConfig,
+	provider IdentityProvider,
+	userRepo repository.IUserRepository,
+	identityRepo repository.IUserIdentityRepository,
+	stateRepo repository.IOIDCStateRepository,
+	tokenService *TokenService,
+	mfaService *MFAService,
+) *SSOService {
+	return &SSOService{
+		cfg:          cfg,
+		provider:     provider,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		stateRepo:    stateRepo,
+		tokenService: tokenService,
+		mfaService:   mfaService,
+	}
+}
+
+// Authorize 建立授權請求，返回 IdP 的授權網址
+func (s *SSOService) Authorize(ctx context.Context) (*dto.OIDCAuthorizeResponse, error) {
+	if !s.cfg.Enabled {
+		return nil, apperrors.New(apperrors.ErrNotFound, "sso is not enabled")
+	}
+
+	// 隨機 Token 為 43 字元的 base64url，亦符合 PKCE code_verifier 的格式
+	var values [3]string
+	for i := range values {
+		token, err := newRandomToken()
+		if err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInternalError, "failed to generate token", err)
+		}
+		values[i] = token
+	}
+	state, nonce, verifier := values[0], values[1], values[2]
+
+	if err := s.stateRepo.Save(ctx, state, &entity.OIDCState{Nonce: nonce, CodeVerifier: verifier}, s.cfg.StateTTL); err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalError, "failed to save oidc state", err)
+	}
+
+	url, err := s.provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrExternalAPI, "identity provider unavailable", err)
+	}
+	return &dto.OIDCAuthorizeResponse{AuthorizationURL: url, Binding: stateBinding(state)}, nil
+}
+
+// Callback 完成授權，登入或建立用戶後簽發 Token；需要 MFA 時返回 Challenge
+func (s *SSOService) Callback(ctx context.Context, req *dto.OIDCCallbackRequest, client *dto.ClientInfo) (*dto.LoginResponse, error) {
+	if !s.cfg.Enabled {
+		return nil, apperrors.New(apperrors.ErrNotFound, "sso is not enabled")
+	}
+
+	// state 須與發起授權的瀏覽器綁定，避免攻擊者將自己的授權結果送入受害者的瀏覽器完成登入
+	if subtle.ConstantTimeCompare([]byte(stateBinding(req.State)), []byte(req.Binding)) != 1 {
+		logger.Warn("OIDC state binding mismatch", zap.String("ip", client.IP))
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "invalid or expired state")
+	}
+
+	state, err := s.stateRepo.Consume(ctx, req.State)
+	if errors.Is(err, repository.ErrOIDCStateNotFound) {
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "invalid or expired state")
+	}
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalError, "failed to load oidc state", err)
+	}
+
+	profile, err := s.provider.Exchange(ctx, req.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		logger.Warn("OIDC login failed", zap.String("ip", client.IP), zap.Error(err))
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "sso login failed")
+	}
+
+	user, err := s.resolveUser(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive() {
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "account has been disabled")
+	}
+
+	if s.cfg.syncsRole(user.Role) && user.AssignRole(s.mapRole(profile.Groups)) {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return nil, err
+		}
+		logger.Info("Role synchronized from identity provider",
+			zap.String("user_id", user.ID),
+			zap.String("role", user.Role.String()),
+		)
+	}
+
+	if s.mfaService.Required(user) {
+		return s.mfaService.Challenge(ctx, user)
+	}
+
+	user.RecordLogin(time.Now())
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		logger.Warn("Failed to record login time", zap.String("user_id", user.ID), zap.Error(err))
+	}
+
+	tokens, err := s.tokenService.Issue(ctx, user.ID, user.Username, user.Role.String(), client)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.LoginResponse{
+		User:   toUserResponse(user),
+		Tokens: tokens,
+	}, nil
+}
+
+// resolveUser 取得外部身份對應的用戶，必要時綁定或建立
+func (s *SSOService) resolveUser(ctx context.Context, profile *entity.ExternalProfile) (*entity.User, error) {
+	identity, err := s.identityRepo.FindByProviderSubject(ctx, profile.Provider, profile.Subject)
+	if err == nil {
+		user, err := s.userRepo.FindByID(ctx, identity.UserID)
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, apperrors.New(apperrors.ErrUnauthorized, "account has been disabled")
+		}
+		return user, err
+	}
+	if !errors.Is(err, repository.ErrIdentityNotFound) {
+		return nil, err
+	}
+
+	email := normalizeEmail(profile.Email)
+
+	// 只信任 IdP 已驗證的 Email，避免以未驗證的 Email 接管既有帳號
+	if email != "" && profile.EmailVerified {
+		user, err := s.userRepo.FindByEmail(ctx, email)
+		if err == nil {
+			if err := s.link(ctx, user, profile); err != nil {
+				return nil, err
+			}
+			return user, nil
+		}
+		if !errors.Is(err, repository.ErrUserNotFound) {
+			return nil, err
+		}
+	}
+
+	if !s.cfg.AutoProvision {
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "account is not registered")
+	}
+	if email == "" {
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "identity provider did not return an email")
+	}
+	return s.provision(ctx, profile, email)
+}
+
+// link 將外部身份綁定至既有用戶
+func (s *SSOService) link(ctx context.Context, user *entity.User, profile *entity.ExternalProfile) error {
+	err := s.identityRepo.Link(ctx, &entity.UserIdentity{
+		ID:       uuid.NewString(),
+		UserID:   user.ID,
+		Provider: profile.Provider,
+		Subject:  profile.Subject,
+		Email:    profile.Email,
+	})
+	if err != nil && !errors.Is(err, repository.ErrIdentityAlreadyLinked) {
+		return err
+	}
+
+	logger.Info("External identity linked",
+		zap.String("user_id", user.ID),
+		zap.String("provider", profile.Provider),
+	)
+	return nil
+}
+
+// provision 建立用戶並綁定外部身份，用戶名稱重複時加上隨機後綴重試一次
+func (s *SSOService) provision(ctx context.Context, profile *entity.ExternalProfile, email string) (*entity.User, error) {
+	username := provisionUsername(profile)
+
+	for attempt := 0; attempt < 2; attempt++ {
+		if attempt > 0 {
+			username = fmt.Sprintf("%s%06d", username, rand.IntN(1000000))
+		}
+
+		// 沒有密碼，僅能以 SSO 登入，可透過忘記密碼流程設定
+		user := entity.NewUser(uuid.NewString(), username, email, "", time.Now())
+		if profile.Name != "" {
+			user.DisplayName = profile.Name
+		}
+		if profile.EmailVerified {
+			user.VerifyEmail()
+		}
+		if len(s.cfg.RoleMappings) > 0 {
+			user.AssignRole(s.mapRole(profile.Groups))
+		}
+
+		identity := &entity.UserIdentity{
+			ID:       uuid.NewString(),
+			UserID:   user.ID,
+			Provider: profile.Provider,
+			Subject:  profile.Subject,
+			Email:    profile.Email,
+		}
+
+		err := s.identityRepo.Provision(ctx, user, identity)
+		switch {
+		case err == nil:
+			logger.Info("User provisioned from identity provider",
+				zap.String("user_id", user.ID),
+				zap.String("provider", profile.Provider),
+			)
+			return user, nil
+		case errors.Is(err, repository.ErrIdentityAlreadyLinked):
+			// 同一身份同時登入，由另一個請求完成建立
+			return s.resolveUser(ctx, profile)
+		case errors.Is(err, repository.ErrUserAlreadyExists):
+			if _, err := s.userRepo.FindByEmail(ctx, email); err == nil {
+				return nil, apperrors.New(apperrors.ErrAlreadyExists, "email already registered, please login with password")
+			}
+		default:
+			return nil, err
+		}
+	}
+	return nil, apperrors.New(apperrors.ErrAlreadyExists, "username already registered")
+}
+
+// stateBinding state 的瀏覽器綁定值，Cookie 中只保存雜湊
+func stateBinding(state string) string {
+	return crypto.SHA256(state)
+}
+
+// mapRole 依群組決定角色
+func (s *SSOService) mapRole(groups []string) consts.Role {
+	for _, mapping := range s.cfg.RoleMappings {
+		for _, group := range groups {
+			if group == mapping.Group {
+				return mapping.Role
+			}
+		}
+	}
+	return s.cfg.DefaultRole
+}
+
+// provisionUsername 以 preferred_username 或 Email 帳號產生符合註冊規則的用戶名稱（英數字，3 至 64 字元）
+func provisionUsername(profile *entity.ExternalProfile) string {
+	source := profile.Username
+	if source == "" {
+		source, _, _ = strings.Cut(profile.Email, "@")
+	}
+
+	username := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return -1
+	}, source)
+
+	if len(username) > 50 {
+		username = username[:50] // 保留重試後綴的長度
+	}
+	if len(username) < 3 {
+		username = "user" + username
+	}
+	return username
+}
@@ -0,0 +1,462 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/url"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"sync_drive_backend/internal/common/consts"
+	"sync_drive_backend/internal/core/auth/application/dto"
+	"sync_drive_backend/internal/core/auth/domain/entity"
+	"sync_drive_backend/internal/infrastructure/oidc"
+	"sync_drive_backend/internal/infrastructure/oidc/idptest"
+	apperrors "sync_drive_backend/pkg/errors"
+	"sync_drive_backend/pkg/jwt"
+	"sync_drive_backend/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Log = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// ssoTestEnv 以測試 IdP 與記憶體儲存庫組成的單一登入服務
+type ssoTestEnv struct {
+	idp        *idptest.Server
+	svc        *SSOService
+	users      *memUserRepository
+	identities *memIdentityRepository
+	challenges *memMFAChallengeRepository
+}
+
+// newSSOTestEnv 創建測試環境，mfaRoles 為強制 MFA 的角色
+func newSSOTestEnv(t *testing.T, cfg *SSOConfig, mfaRoles ...consts.Role) *ssoTestEnv {
+	t.Helper()
+
+	idp := idptest.NewServer(t)
+	provider := oidc.NewProvider(&oidc.Config{
+		IssuerURL:    idp.URL,
+		ClientID:     idp.ClientID,
+		ClientSecret: idp.ClientSecret,
+		RedirectURL:  idp.RedirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+		GroupsClaim:  "groups",
+		Timeout:      5 * time.Second,
+	})
+
+	keys, err := jwt.NewKeySet(&jwt.KeySetConfig{
+		Keys:         []jwt.KeyConfig{{ID: "k1", Algorithm: jwt.AlgHS256, Secret: "test-secret-test-secret-test-secret"}},
+		SigningKeyID: "k1",
+		Issuer:       "sync-drive",
+		Audience:     "sync-drive-api",
+	})
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+
+	users := newMemUserRepository()
+	identities := newMemIdentityRepository(users)
+	challenges := newMemMFAChallengeRepository()
+
+	tokenService := NewTokenService(&TokenConfig{AccessExpireHours: 1, RefreshExpireHours: 24}, keys, newMemSessionRepository(), users)
+	mfaService := NewMFAService(&MFAConfig{
+		Issuer:            "Sync Drive",
+		RequiredRoles:     mfaRoles,
+		ChallengeTTL:      5 * time.Minute,
+		MaxAttempts:       5,
+		RecoveryCodeCount: 10,
+	}, users, challenges, nil, tokenService, NewLoginGuard(&LockoutConfig{}, nil))
+
+	cfg.Enabled = true
+	cfg.StateTTL = 10 * time.Minute
+
+	return &ssoTestEnv{
+		idp:        idp,
+		svc:        NewSSOService(cfg, provider, users, identities, newMemOIDCStateRepository(), tokenService, mfaService),
+		users:      users,
+		identities: identities,
+		challenges: challenges,
+	}
+}
+
+// authorize 建立授權請求並於 IdP 完成登入，返回 callback 請求
+func (e *ssoTestEnv) authorize(t *testing.T, claims map[string]any) *dto.OIDCCallbackRequest {
+	t.Helper()
+
+	resp, err := e.svc.Authorize(context.Background())
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	u, err := url.Parse(resp.AuthorizationURL)
+	if err != nil {
+		t.Fatalf("parse authorization url: %v", err)
+	}
+
+	return &dto.OIDCCallbackRequest{
+		Code:    e.idp.Authorize(t, resp.AuthorizationURL, claims),
+		State:   u.Query().Get("state"),
+		Binding: resp.Binding,
+	}
+}
+
+// login 完成一次單一登入
+func (e *ssoTestEnv) login(t *testing.T, claims map[string]any) (*dto.LoginResponse, error) {
+	t.Helper()
+	return e.svc.Callback(context.Background(), e.authorize(t, claims), &dto.ClientInfo{IP: "203.0.113.1"})
+}
+
+// addUser 建立既有用戶
+func (e *ssoTestEnv) addUser(t *testing.T, id, username, email string) *entity.User {
+	t.Helper()
+
+	user := entity.NewUser(id, username, email, "hash", time.Now())
+	if err := e.users.Create(context.Background(), user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+// assertCode 檢查錯誤為指定錯誤碼的 AppError
+func assertCode(t *testing.T, err error, code int) {
+	t.Helper()
+
+	if err == nil {
+		t.Fatalf("error = nil, want code %d", code)
+	}
+	if !apperrors.IsCode(err, code) {
+		t.Fatalf("error = %v, want code %d", err, code)
+	}
+}
+
+func TestSSOCallbackIssuesTokens(t *testing.T) {
+	env := newSSOTestEnv(t, &SSOConfig{AutoProvision: true})
+
+	resp, err := env.login(t, map[string]any{
+		"sub":                "alice-sub",
+		"email":              "Alice@Example.com",
+		"email_verified":     true,
+		"name":               "Alice",
+		"preferred_username": "alice",
+	})
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if resp.Tokens == nil || resp.Tokens.AccessToken == "" || resp.MFARequired {
+		t.Fatalf("response = %+v, want tokens", resp)
+	}
+	if resp.User.Username != "alice" || resp.User.Email != "alice@example.com" {
+		t.Errorf("user = %s <%s>", resp.User.Username, resp.User.Email)
+	}
+
+	user, err := env.users.FindByUsername(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("provisioned user not found: %v", err)
+	}
+	if !user.EmailVerified || user.DisplayName != "Alice" || user.LastLoginAt == nil {
+		t.Errorf("provisioned user = %+v", user)
+	}
+	if identities := env.identities.byUser(user.ID); len(identities) != 1 || identities[0].Subject != "alice-sub" {
+		t.Errorf("identities = %+v", identities)
+	}
+
+	// 之後以同一外部身份登入，不再建立用戶
+	resp, err = env.login(t, map[string]any{"sub": "alice-sub", "email": "alice@example.com", "email_verified": true})
+	if err != nil {
+		t.Fatalf("second Callback: %v", err)
+	}
+	if resp.User.ID != user.ID {
+		t.Errorf("second login user = %s, want %s", resp.User.ID, user.ID)
+	}
+}
+
+func TestSSOCallbackStateConsumedOnce(t *testing.T) {
+	env := newSSOTestEnv(t, &SSOConfig{AutoProvision: true})
+	claims := map[string]any{"email": "alice@example.com", "email_verified": true}
+
+	req := env.authorize(t, claims)
+	if _, err := env.svc.Callback(context.Background(), req, &dto.ClientInfo{}); err != nil {
+		t.Fatalf("first Callback: %v", err)
+	}
+
+	// 重送相同 state，即使帶著新的授權碼也應拒絕
+	replay := env.authorize(t, claims)
+	replay.State, replay.Binding = req.State, req.Binding
+	_, err := env.svc.Callback(context.Background(), replay, &dto.ClientInfo{})
+	assertCode(t, err, apperrors.ErrUnauthorized)
+
+	_, err = env.svc.Callback(context.Background(), &dto.OIDCCallbackRequest{Code: replay.Code, State: "unknown"}, &dto.ClientInfo{})
+	assertCode(t, err, apperrors.ErrUnauthorized)
+}
+
+func TestSSOCallbackRequiresStateBinding(t *testing.T) {
+	env := newSSOTestEnv(t, &SSOConfig{AutoProvision: true})
+	claims := map[string]any{"email": "alice@example.com", "email_verified": true}
+
+	// 攻擊者取得的 code 與 state 被送入另一個瀏覽器，其 Cookie 屬於另一個授權請求或不存在
+	req := env.authorize(t, claims)
+	other := env.authorize(t, claims)
+	for _, binding := range []string{other.Binding, ""} {
+		_, err := env.svc.Callback(context.Background(), &dto.OIDCCallbackRequest{Code: req.Code, State: req.State, Binding: binding}, &dto.ClientInfo{})
+		assertCode(t, err, apperrors.ErrUnauthorized)
+	}
+	if _, err := env.users.FindByEmail(context.Background(), "alice@example.com"); err == nil {
+		t.Fatal("user provisioned despite mismatched state binding")
+	}
+
+	// 綁定不符時不消耗 state，原瀏覽器仍可完成登入
+	if _, err := env.svc.Callback(context.Background(), req, &dto.ClientInfo{}); err != nil {
+		t.Fatalf("Callback with matching binding: %v", err)
+	}
+}
+
+func TestSSOCallbackRejectsInvalidIDToken(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		claims map[string]any
+		setup  func(idp *idptest.Server)
+	}{
+		{name: "nonce mismatch", claims: map[string]any{"nonce": "replayed-nonce"}},
+		{name: "missing nonce", claims: map[string]any{"nonce": ""}},
+		{name: "bad signature", setup: func(idp *idptest.Server) { idp.SignWith(otherKey) }},
+		{name: "wrong audience", claims: map[string]any{"aud": "another-client"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newSSOTestEnv(t, &SSOConfig{AutoProvision: true})
+			if tt.setup != nil {
+				tt.setup(env.idp)
+			}
+
+			claims := map[string]any{"email": "alice@example.com", "email_verified": true}
+			for k, v := range tt.claims {
+				claims[k] = v
+			}
+
+			_, err := env.login(t, claims)
+			assertCode(t, err, apperrors.ErrUnauthorized)
+
+			if _, err := env.users.FindByEmail(context.Background(), "alice@example.com"); err == nil {
+				t.Error("user provisioned from a rejected id_token")
+			}
+		})
+	}
+}
+
+func TestSSOCallbackLinksOnlyVerifiedEmail(t *testing.T) {
+	t.Run("verified", func(t *testing.T) {
+		env := newSSOTestEnv(t, &SSOConfig{})
+		existing := env.addUser(t, "user-1", "alice", "alice@example.com")
+
+		resp, err := env.login(t, map[string]any{"sub": "alice-sub", "email": "alice@example.com", "email_verified": true})
+		if err != nil {
+			t.Fatalf("Callback: %v", err)
+		}
+		if resp.User.ID != existing.ID {
+			t.Errorf("logged in as %s, want %s", resp.User.ID, existing.ID)
+		}
+		if identities := env.identities.byUser(existing.ID); len(identities) != 1 {
+			t.Errorf("identities = %+v, want one linked identity", identities)
+		}
+	})
+
+	t.Run("unverified without auto provision", func(t *testing.T) {
+		env := newSSOTestEnv(t, &SSOConfig{})
+		existing := env.addUser(t, "user-1", "alice", "alice@example.com")
+
+		_, err := env.login(t, map[string]any{"sub": "mallory-sub", "email": "alice@example.com", "email_verified": false})
+		assertCode(t, err, apperrors.ErrUnauthorized)
+		if identities := env.identities.byUser(existing.ID); len(identities) != 0 {
+			t.Errorf("unverified email linked: %+v", identities)
+		}
+	})
+
+	t.Run("unverified with auto provision", func(t *testing.T) {
+		env := newSSOTestEnv(t, &SSOConfig{AutoProvision: true})
+		existing := env.addUser(t, "user-1", "alice", "alice@example.com")
+
+		_, err := env.login(t, map[string]any{"sub": "mallory-sub", "email": "alice@example.com"})
+		assertCode(t, err, apperrors.ErrAlreadyExists)
+		if identities := env.identities.byUser(existing.ID); len(identities) != 0 {
+			t.Errorf("unverified email linked: %+v", identities)
+		}
+	})
+}
+
+func TestSSOCallbackProvisionUsernameCollision(t *testing.T) {
+	env := newSSOTestEnv(t, &SSOConfig{AutoProvision: true})
+	env.addUser(t, "user-1", "alice", "alice@old.example.com")
+
+	resp, err := env.login(t, map[string]any{
+		"sub":                "alice-sub",
+		"email":              "alice@example.com",
+		"email_verified":     true,
+		"preferred_username": "alice",
+	})
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+
+	if !regexp.MustCompile(`^alice\d{6}$`).MatchString(resp.User.Username) {
+		t.Errorf("username = %q, want alice with a 6-digit suffix", resp.User.Username)
+	}
+	if resp.User.ID == "user-1" || resp.User.Email != "alice@example.com" {
+		t.Errorf("user = %+v, want a new account", resp.User)
+	}
+}
+
+func TestSSOCallbackRoleMapping(t *testing.T) {
+	cfg := func() *SSOConfig {
+		return &SSOConfig{
+			AutoProvision: true,
+			RoleMappings: []RoleMapping{
+				{Group: "fleet-managers", Role: consts.RoleFleetManager},
+				{Group: "drivers", Role: consts.RoleDriver},
+			},
+			DefaultRole: consts.RoleUser,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		groups any
+		want   consts.Role
+	}{
+		{name: "first matching mapping wins", groups: []string{"drivers", "fleet-managers"}, want: consts.RoleFleetManager},
+		{name: "single group", groups: "drivers", want: consts.RoleDriver},
+		{name: "no matching group", groups: []string{"contractors"}, want: consts.RoleUser},
+		{name: "no groups", want: consts.RoleUser},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newSSOTestEnv(t, cfg())
+
+			claims := map[string]any{"email": "alice@example.com", "email_verified": true}
+			if tt.groups != nil {
+				claims["groups"] = tt.groups
+			}
+
+			resp, err := env.login(t, claims)
+			if err != nil {
+				t.Fatalf("Callback: %v", err)
+			}
+			if consts.Role(resp.User.Role) != tt.want {
+				t.Errorf("role = %s, want %s", resp.User.Role, tt.want)
+			}
+		})
+	}
+
+	t.Run("synchronized on every login", func(t *testing.T) {
+		env := newSSOTestEnv(t, cfg())
+
+		if _, err := env.login(t, map[string]any{"email": "alice@example.com", "email_verified": true, "groups": "drivers"}); err != nil {
+			t.Fatalf("first Callback: %v", err)
+		}
+		resp, err := env.login(t, map[string]any{"email": "alice@example.com", "email_verified": true, "groups": "fleet-managers"})
+		if err != nil {
+			t.Fatalf("second Callback: %v", err)
+		}
+		if consts.Role(resp.User.Role) != consts.RoleFleetManager {
+			t.Errorf("role = %s, want %s", resp.User.Role, consts.RoleFleetManager)
+		}
+
+		user, _ := env.users.FindByID(context.Background(), resp.User.ID)
+		if user.Role != consts.RoleFleetManager {
+			t.Errorf("stored role = %s, want %s", user.Role, consts.RoleFleetManager)
+		}
+	})
+
+	t.Run("demoted when leaving a mapped group", func(t *testing.T) {
+		env := newSSOTestEnv(t, cfg())
+
+		if _, err := env.login(t, map[string]any{"email": "alice@example.com", "email_verified": true, "groups": "fleet-managers"}); err != nil {
+			t.Fatalf("first Callback: %v", err)
+		}
+		resp, err := env.login(t, map[string]any{"email": "alice@example.com", "email_verified": true})
+		if err != nil {
+			t.Fatalf("second Callback: %v", err)
+		}
+		if consts.Role(resp.User.Role) != consts.RoleUser {
+			t.Errorf("role = %s, want %s", resp.User.Role, consts.RoleUser)
+		}
+	})
+
+	t.Run("unmapped role kept", func(t *testing.T) {
+		env := newSSOTestEnv(t, cfg())
+		existing := env.addUser(t, "user-1", "alice", "alice@example.com")
+		existing.AssignRole(consts.RoleAdmin)
+		if err := env.users.Update(context.Background(), existing); err != nil {
+			t.Fatalf("update user: %v", err)
+		}
+
+		// 管理員指派的角色不在對應表中，不因群組不符而被改回預設角色
+		resp, err := env.login(t, map[string]any{"email": "alice@example.com", "email_verified": true, "groups": "contractors"})
+		if err != nil {
+			t.Fatalf("Callback: %v", err)
+		}
+		if consts.Role(resp.User.Role) != consts.RoleAdmin {
+			t.Errorf("role = %s, want %s", resp.User.Role, consts.RoleAdmin)
+		}
+	})
+
+	t.Run("unchanged without mappings", func(t *testing.T) {
+		env := newSSOTestEnv(t, &SSOConfig{DefaultRole: consts.RoleDriver})
+		existing := env.addUser(t, "user-1", "alice", "alice@example.com")
+		existing.AssignRole(consts.RoleFleetManager)
+		if err := env.users.Update(context.Background(), existing); err != nil {
+			t.Fatalf("update user: %v", err)
+		}
+
+		resp, err := env.login(t, map[string]any{"email": "alice@example.com", "email_verified": true, "groups": "drivers"})
+		if err != nil {
+			t.Fatalf("Callback: %v", err)
+		}
+		if consts.Role(resp.User.Role) != consts.RoleFleetManager {
+			t.Errorf("role = %s, want %s", resp.User.Role, consts.RoleFleetManager)
+		}
+	})
+}
+
+func TestSSOCallbackRequiresMFAForRole(t *testing.T) {
+	env := newSSOTestEnv(t, &SSOConfig{
+		AutoProvision: true,
+		RoleMappings:  []RoleMapping{{Group: "admins", Role: consts.RoleAdmin}},
+		DefaultRole:   consts.RoleUser,
+	}, consts.RoleAdmin)
+
+	resp, err := env.login(t, map[string]any{"email": "root@example.com", "email_verified": true, "groups": "admins"})
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if !resp.MFARequired || !resp.MFAEnrollmentRequired || resp.MFAToken == "" {
+		t.Errorf("response = %+v, want mfa enrollment challenge", resp)
+	}
+	if resp.Tokens != nil {
+		t.Error("tokens issued before mfa")
+	}
+	if len(env.challenges.challenges) != 1 {
+		t.Errorf("challenges = %d, want 1", len(env.challenges.challenges))
+	}
+
+	// 不需要 MFA 的角色直接簽發 Token
+	resp, err = env.login(t, map[string]any{"sub": "bob-sub", "email": "bob@example.com", "email_verified": true})
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if resp.MFARequired || resp.Tokens == nil {
+		t.Errorf("response = %+v, want tokens", resp)
+	}
+}
@@ -0,0 +1,234 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sync_drive_backend/internal/common/middleware/auth"
+	"sync_drive_backend/internal/core/auth/application/dto"
+	"sync_drive_backend/internal/core/auth/domain/entity"
+	"sync_drive_backend/internal/core/auth/domain/repository"
+	apperrors "sync_drive_backend/pkg/errors"
+	"sync_drive_backend/pkg/jwt"
+	"sync_drive_backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// TokenConfig Token 配置
+type TokenConfig struct {
+	AccessExpireHours  int
+	RefreshExpireHours int
+}
+
+// TokenService Token 簽發、輪替、驗證與 Session 管理
+type TokenService struct {
+	cfg         *TokenConfig
+	keys        *jwt.KeySet
+	sessionRepo repository.ISessionRepository
+	userRepo    repository.IUserRepository
+}
+
+// 確保實作介面
+var _ auth.TokenVerifier = (*TokenService)(nil)
+
+// NewTokenService 創建 Token 服務
+func NewTokenService(cfg *TokenConfig, keys *jwt.KeySet, sessionRepo repository.ISessionRepository, userRepo repository.IUserRepository) *TokenService {
+	return &TokenService{
+		cfg:         cfg,
+		keys:        keys,
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// Issue 登入成功後簽發 Token，建立新的登入 Session
+func (s *TokenService) Issue(ctx context.Context, userID, username, roleID string, client *dto.ClientInfo) (*dto.TokenResponse, error) {
+	sessionID := uuid.NewString()
+
+	pair, err := s.keys.GeneratePair(userID, username, roleID, sessionID, s.cfg.AccessExpireHours, s.cfg.RefreshExpireHours)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalError, "failed to generate token", err)
+	}
+
+	now := time.Now()
+	session := &entity.Session{
+		ID:              sessionID,
+		UserID:          userID,
+		CurrentTokenID:  pair.RefreshID,
+		AccessTokenID:   pair.AccessID,
+		AccessExpiresAt: pair.AccessExpiresAt,
+		DeviceID:        client.DeviceID,
+		UserAgent:       client.UserAgent,
+		IP:              client.IP,
+		CreatedAt:       now,
+		LastSeenAt:      now,
+	}
+	if err := s.sessionRepo.Create(ctx, session, s.refreshTTL()); err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalError, "failed to save session", err)
+	}
+
+	return toTokenResponse(pair), nil
+}
+
+// Refresh 以 Refresh Token 換發新的 Token 組，舊的 Refresh Token 隨即失效
+// 已輪替過的 Refresh Token 再次出現代表可能外洩，撤銷整個 Session，該次登入的所有 Token 都需要重新登入
+// 新的 Token 使用用戶目前的名稱與角色；用戶已停用或刪除時撤銷 Session
+func (s *TokenService) Refresh(ctx context.Context, refreshToken string, client *dto.ClientInfo) (*dto.TokenResponse, error) {
+	claims, err := s.keys.ParseRefresh(refreshToken)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "invalid or expired refresh token")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, claims.UserID)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, err
+	}
+	if user == nil || !user.IsActive() {
+		if err := s.sessionRepo.Revoke(ctx, claims.UserID, claims.SessionID); err != nil && !errors.Is(err, repository.ErrSessionNotFound) {
+			logger.Warn("Failed to revoke session of inactive user", zap.String("user_id", claims.UserID), zap.Error(err))
+		}
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "account has been disabled")
+	}
+
+	pair, err := s.keys.GeneratePair(user.ID, user.Username, user.Role.String(), claims.SessionID, s.cfg.AccessExpireHours, s.cfg.RefreshExpireHours)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalError, "failed to generate token", err)
+	}
+
+	session := &entity.Session{
+		ID:              claims.SessionID,
+		UserID:          claims.UserID,
+		CurrentTokenID:  pair.RefreshID,
+		AccessTokenID:   pair.AccessID,
+		AccessExpiresAt: pair.AccessExpiresAt,
+		IP:              client.IP,
+		LastSeenAt:      time.Now(),
+	}
+	err = s.sessionRepo.Rotate(ctx, session, claims.ID, s.refreshTTL())
+	switch {
+	case errors.Is(err, repository.ErrRefreshTokenReused):
+		logger.Warn("Refresh token reuse detected, session revoked",
+			zap.String("user_id", claims.UserID),
+			zap.String("session_id", claims.SessionID),
+			zap.String("token_id", claims.ID),
+			zap.String("ip", client.IP),
+		)
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "refresh token has been revoked")
+	case errors.Is(err, repository.ErrSessionNotFound):
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "refresh token has been revoked")
+	case err != nil:
+		return nil, apperrors.Wrap(apperrors.ErrInternalError, "failed to rotate refresh token", err)
+	}
+
+	return toTokenResponse(pair), nil
+}
+
+// Logout 登出，撤銷 Refresh Token 所屬的 Session
+func (s *TokenService) Logout(ctx context.Context, refreshToken string) error {
+	claims, err := s.keys.ParseRefresh(refreshToken)
+	if err != nil {
+		return apperrors.New(apperrors.ErrUnauthorized, "invalid or expired refresh token")
+	}
+
+	err = s.sessionRepo.Revoke(ctx, claims.UserID, claims.SessionID)
+	if err != nil && !errors.Is(err, repository.ErrSessionNotFound) {
+		return apperrors.Wrap(apperrors.ErrInternalError, "failed to revoke session", err)
+	}
+	return nil
+}
+
+// Parse 僅驗證 Access Token 簽章與時效
+func (s *TokenService) Parse(token string) (*jwt.Claims, error) {
+	return s.keys.Parse(token)
+}
+
+// Verify 驗證 Access Token 並檢查是否已被撤銷
+// Redis 不可用時僅依簽章與時效判斷，避免所有已登入的請求失敗
+func (s *TokenService) Verify(ctx context.Context, token string) (*jwt.Claims, error) {
+	claims, err := s.keys.Parse(token)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "invalid or expired token")
+	}
+
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+
+	revoked, err := s.sessionRepo.IsAccessRevoked(ctx, claims.UserID, claims.SessionID, claims.ID, issuedAt, time.Now())
+	if err != nil {
+		logger.Warn("Failed to check token revocation", zap.String("user_id", claims.UserID), zap.Error(err))
+		return claims, nil
+	}
+	if revoked {
+		return nil, apperrors.New(apperrors.ErrUnauthorized, "token has been revoked")
+	}
+	return claims, nil
+}
+
+// JWKS 公開的驗證金鑰
+func (s *TokenService) JWKS() *jwt.JWKS {
+	return s.keys.JWKS()
+}
+
+// ListSessions 取得用戶所有有效的登入 Session
+func (s *TokenService) ListSessions(ctx context.Context, userID, currentSessionID string) ([]*dto.SessionResponse, error) {
+	sessions, err := s.sessionRepo.List(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalError, "failed to list sessions", err)
+	}
+
+	resp := make([]*dto.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		resp = append(resp, &dto.SessionResponse{
+			ID:         session.ID,
+			DeviceID:   session.DeviceID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			Current:    session.ID == currentSessionID,
+		})
+	}
+	return resp, nil
+}
+
+// RevokeSession 撤銷用戶的登入 Session
+func (s *TokenService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	err := s.sessionRepo.Revoke(ctx, userID, sessionID)
+	switch {
+	case errors.Is(err, repository.ErrSessionNotFound):
+		return apperrors.New(apperrors.ErrNotFound, "session not found")
+	case err != nil:
+		return apperrors.Wrap(apperrors.ErrInternalError, "failed to revoke session", err)
+	}
+	return nil
+}
+
+// RevokeAll 撤銷用戶所有 Token（全部登出），用於停用帳號、變更密碼或用戶主動登出所有裝置
+func (s *TokenService) RevokeAll(ctx context.Context, userID string) error {
+	if err := s.sessionRepo.RevokeAll(ctx, userID, time.Now(), s.refreshTTL()); err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalError, "failed to revoke sessions", err)
+	}
+	return nil
+}
+
+// refreshTTL Refresh Token 有效期，亦為 Token 的最長有效期
+func (s *TokenService) refreshTTL() time.Duration {
+	return time.Duration(s.cfg.RefreshExpireHours) * time.Hour
+}
+
+// toTokenResponse 轉換為響應
+func toTokenResponse(pair *jwt.TokenPair) *dto.TokenResponse {
+	now := time.Now()
+	return &dto.TokenResponse{
+		AccessToken:      pair.AccessToken,
+		RefreshToken:     pair.RefreshToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(pair.AccessExpiresAt.Sub(now).Seconds()),
+		RefreshExpiresIn: int64(pair.RefreshExpiresAt.Sub(now).Seconds()),
+	}
+}
@@ -0,0 +1,236 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"time"
+
+	"sync_drive_backend/internal/core/auth/application/dto"
+	"sync_drive_backend/internal/core/auth/domain/entity"
+	"sync_drive_backend/internal/core/auth/domain/repository"
+	"sync_drive_backend/internal/core/auth/domain/service"
+	"sync_drive_backend/pkg/crypto"
+	apperrors "sync_drive_backend/pkg/errors"
+	"sync_drive_backend/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// forgotPasswordTimeout 背景寄送重設密碼信的逾時時間
+const forgotPasswordTimeout = 30 * time.Second
+
+// 郵件範本名稱
+const (
+	templateVerifyEmail   = "verify_email"
+	templateResetPassword = "reset_password"
+)
+
+// EmailSender 以範本寄送郵件
+type EmailSender interface {
+	SendTemplate(ctx context.Context, to, name, locale string, data any) error
+}
+
+// VerificationConfig Email 驗證與重設密碼配置
+type VerificationConfig struct {
+	FrontendURL           string        // 前端網址，郵件中的連結為 <FrontendURL>/verify-email?token=...
+	EmailVerificationTTL  time.Duration // Email 驗證連結有效時間
+	PasswordResetTTL      time.Duration // 重設密碼連結有效時間
+	PasswordResetCooldown time.Duration // 同一 Email 重設密碼信的寄送間隔，0 表示不限制
+}
+
+// VerificationService Email 驗證與重設密碼
+// Token 為隨機值，僅保存 SHA256 雜湊，使用一次即失效
+type VerificationService struct {
+	cfg          *VerificationConfig
+	userRepo     repository.IUserRepository
+	tokenRepo    repository.IVerificationTokenRepository
+	domainSvc    *service.AuthDomainService
+	tokenService *TokenService
+	emailSender  EmailSender
+}
+
+// NewVerificationService 創建驗證服務
+func NewVerificationService(
+	cfg *VerificationConfig,
+	userRepo repository.IUserRepository,
+	tokenRepo repository.IVerificationTokenRepository,
+	domainSvc *service.AuthDomainService,
+	tokenService *TokenService,
+	emailSender EmailSender,
+) *VerificationService {
+	return &VerificationService{
+		cfg:          cfg,
+		userRepo:     userRepo,
+		tokenRepo:    tokenRepo,
+		domainSvc:    domainSvc,
+		tokenService: tokenService,
+		emailSender:  emailSender,
+	}
+}
+
+// SendEmailVerification 寄送 Email 驗證信
+func (s *VerificationService) SendEmailVerification(ctx context.Context, userID, locale string) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return apperrors.New(apperrors.ErrNotFound, "user not found")
+	}
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		return apperrors.New(apperrors.ErrInvalidParams, "email already verified")
+	}
+
+	return s.send(ctx, user, entity.TokenPurposeEmailVerification, templateVerifyEmail, "/verify-email", s.cfg.EmailVerificationTTL, locale)
+}
+
+// VerifyEmail 驗證 Email
+func (s *VerificationService) VerifyEmail(ctx context.Context, req *dto.VerifyEmailRequest) error {
+	user, err := s.consume(ctx, entity.TokenPurposeEmailVerification, req.Token)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		return nil
+	}
+
+	user.VerifyEmail()
+	return s.userRepo.Update(ctx, user)
+}
+
+// ForgotPassword 寄送重設密碼信
+// 無論帳號是否存在都立即返回成功，查詢與寄送在背景進行，避免由回應內容或時間探測已註冊的 Email
+// 同一 Email 在冷卻時間內的重複請求直接忽略，回應不變
+func (s *VerificationService) ForgotPassword(ctx context.Context, req *dto.ForgotPasswordRequest, locale string) error {
+	email := normalizeEmail(req.Email)
+	if s.cfg.PasswordResetCooldown > 0 {
+		acquired, err := s.tokenRepo.AcquireCooldown(ctx, entity.TokenPurposePasswordReset, crypto.SHA256(email), s.cfg.PasswordResetCooldown)
+		if err != nil {
+			logger.Error("Failed to acquire password reset cooldown", zap.Error(err))
+			return nil
+		}
+		if !acquired {
+			return nil
+		}
+	}
+
+	bgCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), forgotPasswordTimeout)
+
+	go func() {
+		defer cancel()
+		if err := s.sendPasswordReset(bgCtx, email, locale); err != nil {
+			logger.Error("Failed to send password reset email", zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// sendPasswordReset 查詢帳號並寄送重設密碼信，帳號不存在或已停用時不寄送
+func (s *VerificationService) sendPasswordReset(ctx context.Context, email, locale string) error {
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.IsActive() {
+		return nil
+	}
+
+	return s.send(ctx, user, entity.TokenPurposePasswordReset, templateResetPassword, "/reset-password", s.cfg.PasswordResetTTL, locale)
+}
+
+// ResetPassword 以重設密碼 Token 設定新密碼，並撤銷所有已簽發的 Token
+func (s *VerificationService) ResetPassword(ctx context.Context, req *dto.ResetPasswordRequest) error {
+	// 先檢查密碼強度，避免 Token 因密碼不符規則而被消耗
+	if err := s.domainSvc.ValidatePassword(req.NewPassword); err != nil {
+		return toPasswordError(err)
+	}
+
+	user, err := s.consume(ctx, entity.TokenPurposePasswordReset, req.Token)
+	if err != nil {
+		return err
+	}
+	if !user.IsActive() {
+		return apperrors.New(apperrors.ErrUnauthorized, "account has been disabled")
+	}
+
+	passwordHash, err := s.domainSvc.HashPassword(req.NewPassword)
+	if err != nil {
+		return toPasswordError(err)
+	}
+
+	user.ChangePassword(passwordHash, time.Now())
+	// 能收到重設密碼信即證明擁有該 Email
+	user.VerifyEmail()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	return s.tokenService.RevokeAll(ctx, user.ID)
+}
+
+// send 簽發 Token 並寄送郵件
+func (s *VerificationService) send(ctx context.Context, user *entity.User, purpose entity.TokenPurpose, template, path string, ttl time.Duration, locale string) error {
+	token, err := newRandomToken()
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalError, "failed to generate token", err)
+	}
+	if err := s.tokenRepo.Save(ctx, purpose, crypto.SHA256(token), user.ID, ttl); err != nil {
+		return err
+	}
+
+	name := user.DisplayName
+	if name == "" {
+		name = user.Username
+	}
+	data := map[string]any{
+		"Name":           name,
+		"URL":            s.cfg.FrontendURL + path + "?token=" + url.QueryEscape(token),
+		"ExpiresHours":   int(ttl / time.Hour),
+		"ExpiresMinutes": int(ttl / time.Minute),
+	}
+	if ttl%time.Hour != 0 {
+		data["ExpiresHours"] = 0
+	}
+
+	if err := s.emailSender.SendTemplate(ctx, user.Email, template, locale, data); err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalError, "failed to send email", err)
+	}
+
+	logger.Info("Verification email sent",
+		zap.String("user_id", user.ID),
+		zap.String("purpose", string(purpose)),
+	)
+	return nil
+}
+
+// consume 消耗 Token 並取得對應的用戶
+func (s *VerificationService) consume(ctx context.Context, purpose entity.TokenPurpose, token string) (*entity.User, error) {
+	userID, err := s.tokenRepo.Consume(ctx, purpose, crypto.SHA256(token))
+	if errors.Is(err, repository.ErrVerificationTokenNotFound) {
+		return nil, apperrors.New(apperrors.ErrInvalidParams, "invalid or expired token")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil, apperrors.New(apperrors.ErrInvalidParams, "invalid or expired token")
+	}
+	return user, err
+}
+
+// newRandomToken 產生 256 bits 的隨機 Token
+func newRandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
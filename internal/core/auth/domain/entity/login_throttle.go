@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+// LockoutPolicy 登入失敗節流規則
+// 前 FreeAttempts 次失敗不延遲，之後每次失敗的等待時間自 BaseDelay 起倍增（上限 MaxDelay），
+// 失敗達 LockThreshold 次時鎖定 LockDuration；失敗計數於最後一次失敗後 Window 內有效
+type LockoutPolicy struct {
+	FreeAttempts  int
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	LockThreshold int
+	LockDuration  time.Duration
+	Window        time.Duration
+}
+
+// LoginThrottle 登入節流狀態
+type LoginThrottle struct {
+	Failures   int
+	RetryAt    time.Time // 下次允許嘗試登入的時間，零值表示不限制
+	Locked     bool      // 已達鎖定門檻
+	JustLocked bool      // 本次失敗觸發鎖定
+}
+
+// Blocked 目前是否禁止嘗試登入
+func (t *LoginThrottle) Blocked(now time.Time) bool {
+	return now.Before(t.RetryAt)
+}
@@ -0,0 +1,7 @@
+package entity
+
+// MFAChallenge 密碼驗證通過、等待第二步驗證的登入
+type MFAChallenge struct {
+	UserID     string
+	Enrollment bool // 角色強制 MFA 但尚未綁定，需先完成綁定
+}
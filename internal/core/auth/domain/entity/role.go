@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"regexp"
+	"time"
+
+	"sync_drive_backend/internal/common/consts"
+)
+
+// roleNamePattern 自訂角色名稱格式
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,31}$`)
+
+// Role 角色與其權限
+// 內建角色（consts.Role 定義的角色）由程式碼使用，不可刪除，但權限可調整
+type Role struct {
+	Name        consts.Role
+	Description string
+	BuiltIn     bool
+	Permissions []consts.Permission
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewRole 創建自訂角色
+func NewRole(name consts.Role, description string, permissions []consts.Permission) *Role {
+	return &Role{
+		Name:        name,
+		Description: description,
+		Permissions: permissions,
+	}
+}
+
+// ValidRoleName 驗證角色名稱格式（小寫英數字與底線，2 至 32 字元）
+func ValidRoleName(name consts.Role) bool {
+	return roleNamePattern.MatchString(string(name))
+}
+
+// HasPermission 是否具備指定權限
+func (r *Role) HasPermission(permission consts.Permission) bool {
+	for _, p := range r.Permissions {
+		if p.Covers(permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// Permission 權限定義
+type Permission struct {
+	Code        consts.Permission
+	Description string
+	CreatedAt   time.Time
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// Session 登入 Session
+// 同一次登入輪替出的 Refresh Token 屬於同一個 Session，任何時間只有 CurrentTokenID 有效
+type Session struct {
+	ID              string
+	UserID          string
+	CurrentTokenID  string    // 目前有效的 Refresh Token jti
+	AccessTokenID   string    // 最近一次簽發的 Access Token jti，撤銷時加入黑名單
+	AccessExpiresAt time.Time // 最近一次簽發的 Access Token 到期時間
+	DeviceID        string
+	UserAgent       string
+	IP              string
+	CreatedAt       time.Time
+	LastSeenAt      time.Time // 最近一次以此 Session 的 Token 存取 API 或換發 Token
+}
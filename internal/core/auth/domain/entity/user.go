@@ -0,0 +1,134 @@
+package entity
+
+import (
+	"time"
+
+	"sync_drive_backend/internal/common/consts"
+)
+
+// UserStatus 用戶狀態
+type UserStatus string
+
+const (
+	UserStatusActive   UserStatus = "active"   // 正常
+	UserStatusDisabled UserStatus = "disabled" // 已停用，無法登入
+)
+
+// User 用戶
+type User struct {
+	ID                string
+	Username          string
+	Email             string
+	PasswordHash      string
+	DisplayName       string
+	Phone             string
+	Role              consts.Role
+	Status            UserStatus
+	EmailVerified     bool
+	PasswordChangedAt time.Time
+	LastLoginAt       *time.Time
+	MFAEnabled        bool     // 已完成 TOTP 綁定
+	MFASecret         string   // 加密後的 TOTP 金鑰，綁定確認前 MFAEnabled 為 false
+	MFALastCounter    int64    // 最後使用的 TOTP 時間步，防止驗證碼重放
+	RecoveryCodes     []string // 未使用的復原碼雜湊
+	Version           int64    // 樂觀鎖版本號
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// NewUser 創建新用戶，預設為一般用戶角色
+func NewUser(id, username, email, passwordHash string, now time.Time) *User {
+	return &User{
+		ID:                id,
+		Username:          username,
+		Email:             email,
+		PasswordHash:      passwordHash,
+		DisplayName:       username,
+		Role:              consts.RoleUser,
+		Status:            UserStatusActive,
+		PasswordChangedAt: now,
+	}
+}
+
+// IsActive 是否可登入
+func (u *User) IsActive() bool {
+	return u.Status == UserStatusActive
+}
+
+// UpdateProfile 更新個人資料
+func (u *User) UpdateProfile(displayName, phone string) {
+	u.DisplayName = displayName
+	u.Phone = phone
+}
+
+// ChangePassword 變更密碼
+func (u *User) ChangePassword(passwordHash string, now time.Time) {
+	u.PasswordHash = passwordHash
+	u.PasswordChangedAt = now
+}
+
+// AssignRole 指派角色，返回角色是否變更
+func (u *User) AssignRole(role consts.Role) bool {
+	if u.Role == role {
+		return false
+	}
+	u.Role = role
+	return true
+}
+
+// VerifyEmail 標記 Email 已驗證
+func (u *User) VerifyEmail() {
+	u.EmailVerified = true
+}
+
+// RecordLogin 記錄登入時間
+func (u *User) RecordLogin(now time.Time) {
+	u.LastLoginAt = &now
+}
+
+// BeginMFAEnrollment 開始綁定 TOTP，確認前不啟用
+func (u *User) BeginMFAEnrollment(encryptedSecret string) {
+	u.MFAEnabled = false
+	u.MFASecret = encryptedSecret
+	u.MFALastCounter = 0
+	u.RecoveryCodes = nil
+}
+
+// EnableMFA 確認綁定並設定復原碼
+func (u *User) EnableMFA(recoveryCodeHashes []string) {
+	u.MFAEnabled = true
+	u.RecoveryCodes = recoveryCodeHashes
+}
+
+// DisableMFA 解除 TOTP 綁定
+func (u *User) DisableMFA() {
+	u.MFAEnabled = false
+	u.MFASecret = ""
+	u.MFALastCounter = 0
+	u.RecoveryCodes = nil
+}
+
+// UseTOTPCounter 記錄已使用的 TOTP 時間步，時間步不大於上次使用時返回 false（重放）
+func (u *User) UseTOTPCounter(counter int64) bool {
+	if counter <= u.MFALastCounter {
+		return false
+	}
+	u.MFALastCounter = counter
+	return true
+}
+
+// UseRecoveryCode 使用復原碼，每組只能使用一次
+func (u *User) UseRecoveryCode(codeHash string) bool {
+	for i, h := range u.RecoveryCodes {
+		if h == codeHash {
+			u.RecoveryCodes = append(u.RecoveryCodes[:i:i], u.RecoveryCodes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// ReplaceRecoveryCodes 重新產生復原碼，舊的全部失效
+func (u *User) ReplaceRecoveryCodes(recoveryCodeHashes []string) {
+	u.RecoveryCodes = recoveryCodeHashes
+}
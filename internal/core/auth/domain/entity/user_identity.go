@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// UserIdentity 綁定於用戶的外部身份（OIDC），以 Provider（issuer）與 Subject（sub）識別
+type UserIdentity struct {
+	ID        string
+	UserID    string
+	Provider  string
+	Subject   string
+	Email     string // 綁定時 IdP 提供的 Email，僅供參考
+	CreatedAt time.Time
+}
+
+// OIDCState 授權請求的暫存資料，以 state 取回
+type OIDCState struct {
+	Nonce        string
+	CodeVerifier string // PKCE code_verifier
+}
+
+// ExternalProfile IdP 驗證後提供的用戶資訊
+type ExternalProfile struct {
+	Provider      string // issuer
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Username      string // preferred_username
+	Groups        []string
+}
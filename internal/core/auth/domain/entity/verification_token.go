@@ -0,0 +1,9 @@
+package entity
+
+// TokenPurpose 一次性驗證 Token 的用途
+type TokenPurpose string
+
+const (
+	TokenPurposeEmailVerification TokenPurpose = "verify_email"   // 驗證 Email
+	TokenPurposePasswordReset     TokenPurpose = "reset_password" // 重設密碼
+)
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sync_drive_backend/internal/core/auth/domain/entity"
+)
+
+// ILoginAttemptRepository 登入失敗計數儲存庫
+// subject 為節流對象，例如 user:<id>、account:<帳號>、ip:<IP>
+type ILoginAttemptRepository interface {
+	// Get 取得節流狀態，沒有失敗紀錄時返回零值
+	Get(ctx context.Context, subject string) (*entity.LoginThrottle, error)
+	// RecordFailure 記錄一次失敗，依規則計算下次允許嘗試的時間
+	RecordFailure(ctx context.Context, subject string, policy *entity.LockoutPolicy, now time.Time) (*entity.LoginThrottle, error)
+	// Reset 清除失敗紀錄（登入成功或管理員解鎖）
+	Reset(ctx context.Context, subject string) error
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sync_drive_backend/internal/core/auth/domain/entity"
+)
+
+// ErrMFAChallengeNotFound MFA Challenge 不存在（已完成、過期或嘗試次數超過上限）
+var ErrMFAChallengeNotFound = errors.New("mfa challenge not found")
+
+// IMFAChallengeRepository MFA Challenge 儲存庫，只保存 Challenge Token 的雜湊
+type IMFAChallengeRepository interface {
+	// Create 建立 Challenge
+	Create(ctx context.Context, tokenHash string, challenge *entity.MFAChallenge, ttl time.Duration) error
+	// Get 取得 Challenge，不計入嘗試次數
+	Get(ctx context.Context, tokenHash string) (*entity.MFAChallenge, error)
+	// Attempt 計入一次驗證嘗試並取得 Challenge，超過 maxAttempts 時刪除並返回 ErrMFAChallengeNotFound
+	Attempt(ctx context.Context, tokenHash string, maxAttempts int) (*entity.MFAChallenge, error)
+	// Delete 驗證成功後刪除
+	Delete(ctx context.Context, tokenHash string) error
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"sync_drive_backend/internal/common/consts"
+	"sync_drive_backend/internal/core/auth/domain/entity"
+)
+
+var (
+	// ErrRoleNotFound 角色不存在
+	ErrRoleNotFound = errors.New("role not found")
+	// ErrRoleAlreadyExists 角色名稱已被使用
+	ErrRoleAlreadyExists = errors.New("role already exists")
+	// ErrRoleInUse 角色仍有用戶使用
+	ErrRoleInUse = errors.New("role in use")
+	// ErrPermissionNotFound 權限不存在
+	ErrPermissionNotFound = errors.New("permission not found")
+	// ErrPermissionAlreadyExists 權限代碼已被使用
+	ErrPermissionAlreadyExists = errors.New("permission already exists")
+)
+
+// IRoleRepository 角色與權限儲存庫
+type IRoleRepository interface {
+	// ListRoles 列出全部角色（含權限）
+	ListRoles(ctx context.Context) ([]*entity.Role, error)
+	// FindRole 依名稱查詢（含權限），不存在時返回 ErrRoleNotFound
+	FindRole(ctx context.Context, name consts.Role) (*entity.Role, error)
+	// CreateRole 建立角色與其權限，名稱重複時返回 ErrRoleAlreadyExists，權限不存在時返回 ErrPermissionNotFound
+	CreateRole(ctx context.Context, role *entity.Role) error
+	// UpdateRole 更新角色說明，不存在時返回 ErrRoleNotFound
+	UpdateRole(ctx context.Context, role *entity.Role) error
+	// DeleteRole 刪除角色，不存在時返回 ErrRoleNotFound，仍有用戶使用時返回 ErrRoleInUse
+	DeleteRole(ctx context.Context, name consts.Role) error
+	// SetRolePermissions 以指定權限取代角色的全部權限，角色或權限不存在時返回 ErrRoleNotFound 或 ErrPermissionNotFound
+	SetRolePermissions(ctx context.Context, name consts.Role, permissions []consts.Permission) error
+
+	// ListPermissions 列出全部權限
+	ListPermissions(ctx context.Context) ([]*entity.Permission, error)
+	// CreatePermission 建立權限，代碼重複時返回 ErrPermissionAlreadyExists
+	CreatePermission(ctx context.Context, permission *entity.Permission) error
+	// DeletePermission 刪除權限並自所有角色移除，不存在時返回 ErrPermissionNotFound
+	DeletePermission(ctx context.Context, code consts.Permission) error
+}
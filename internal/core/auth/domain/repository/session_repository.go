@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sync_drive_backend/internal/core/auth/domain/entity"
+)
+
+var (
+	// ErrSessionNotFound Session 不存在（已登出、已撤銷或過期）
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrRefreshTokenReused 出示的不是目前有效的 Refresh Token，Session 已被撤銷
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+)
+
+// ISessionRepository 登入 Session 儲存庫
+type ISessionRepository interface {
+	// Create 建立 Session，ttl 為 Refresh Token 的有效期
+	Create(ctx context.Context, session *entity.Session, ttl time.Duration) error
+	// Rotate 以 session.CurrentTokenID 取代 presentedTokenID，更新 Access Token、IP 與最後使用時間並延長有效期
+	// presentedTokenID 不是目前有效的 Token 時撤銷整個 Session 並返回 ErrRefreshTokenReused
+	Rotate(ctx context.Context, session *entity.Session, presentedTokenID string, ttl time.Duration) error
+	// List 取得用戶所有有效的 Session
+	List(ctx context.Context, userID string) ([]*entity.Session, error)
+	// Revoke 撤銷 Session，並將其 Access Token 加入黑名單
+	Revoke(ctx context.Context, userID, sessionID string) error
+	// RevokeAll 撤銷用戶所有 Session，並使 before 之前簽發的 Token 全部失效，ttl 需涵蓋 Token 的最長有效期
+	RevokeAll(ctx context.Context, userID string, before time.Time, ttl time.Duration) error
+	// IsAccessRevoked 檢查 Access Token 是否在黑名單或簽發於用戶的撤銷時間之前
+	// 未撤銷時更新所屬 Session 的最後使用時間
+	IsAccessRevoked(ctx context.Context, userID, sessionID, tokenID string, issuedAt, now time.Time) (bool, error)
+}
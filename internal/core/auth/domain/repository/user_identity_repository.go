@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sync_drive_backend/internal/core/auth/domain/entity"
+)
+
+var (
+	// ErrIdentityNotFound 外部身份尚未綁定任何用戶
+	ErrIdentityNotFound = errors.New("identity not found")
+	// ErrIdentityAlreadyLinked 外部身份已綁定其他用戶
+	ErrIdentityAlreadyLinked = errors.New("identity already linked")
+	// ErrOIDCStateNotFound state 不存在（已使用或過期）
+	ErrOIDCStateNotFound = errors.New("oidc state not found")
+)
+
+// IUserIdentityRepository 外部身份儲存庫
+type IUserIdentityRepository interface {
+	// FindByProviderSubject 依 Provider 與 Subject 查詢，不存在時返回 ErrIdentityNotFound
+	FindByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error)
+	// Link 將外部身份綁定至既有用戶，已綁定時返回 ErrIdentityAlreadyLinked
+	Link(ctx context.Context, identity *entity.UserIdentity) error
+	// Provision 建立用戶並綁定外部身份（同一交易），用戶名稱或 Email 重複時返回 ErrUserAlreadyExists
+	Provision(ctx context.Context, user *entity.User, identity *entity.UserIdentity) error
+}
+
+// IOIDCStateRepository OIDC 授權請求暫存
+type IOIDCStateRepository interface {
+	// Save 保存 state 對應的資料
+	Save(ctx context.Context, state string, data *entity.OIDCState, ttl time.Duration) error
+	// Consume 取出並刪除，不存在時返回 ErrOIDCStateNotFound
+	Consume(ctx context.Context, state string) (*entity.OIDCState, error)
+}
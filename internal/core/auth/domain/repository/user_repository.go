@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"sync_drive_backend/internal/core/auth/domain/entity"
+)
+
+var (
+	// ErrUserNotFound 用戶不存在
+	ErrUserNotFound = errors.New("user not found")
+	// ErrUserAlreadyExists 用戶名稱或 Email 已被使用
+	ErrUserAlreadyExists = errors.New("user already exists")
+)
+
+// IUserRepository 用戶儲存庫
+type IUserRepository interface {
+	// Create 建立用戶，用戶名稱或 Email 重複時返回 ErrUserAlreadyExists
+	Create(ctx context.Context, user *entity.User) error
+	// FindByID 依 ID 查詢，不存在時返回 ErrUserNotFound
+	FindByID(ctx context.Context, id string) (*entity.User, error)
+	// FindByUsername 依用戶名稱查詢，不存在時返回 ErrUserNotFound
+	FindByUsername(ctx context.Context, username string) (*entity.User, error)
+	// FindByEmail 依 Email 查詢，不存在時返回 ErrUserNotFound
+	FindByEmail(ctx context.Context, email string) (*entity.User, error)
+	// Update 更新用戶（樂觀鎖），版本衝突時返回 ErrVersionConflict 的 AppError
+	Update(ctx context.Context, user *entity.User) error
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sync_drive_backend/internal/core/auth/domain/entity"
+)
+
+// ErrVerificationTokenNotFound Token 不存在（已使用、已被新的 Token 取代或過期）
+var ErrVerificationTokenNotFound = errors.New("verification token not found")
+
+// IVerificationTokenRepository 一次性驗證 Token 儲存庫，只保存 Token 的雜湊
+type IVerificationTokenRepository interface {
+	// Save 保存 Token 雜湊，同一用戶同一用途只保留最新的 Token
+	Save(ctx context.Context, purpose entity.TokenPurpose, tokenHash, userID string, ttl time.Duration) error
+	// Consume 取出並刪除 Token，返回用戶 ID，不存在時返回 ErrVerificationTokenNotFound
+	Consume(ctx context.Context, purpose entity.TokenPurpose, tokenHash string) (string, error)
+	// AcquireCooldown 取得寄送冷卻，同一用途同一對象在 ttl 內只有第一次返回 true
+	AcquireCooldown(ctx context.Context, purpose entity.TokenPurpose, subject string, ttl time.Duration) (bool, error)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"errors"
+	"unicode"
+
+	"sync_drive_backend/internal/core/auth/domain/entity"
+	"sync_drive_backend/pkg/crypto"
+)
+
+// minPasswordLength 密碼最短長度
+const minPasswordLength = 8
+
+// ErrWeakPassword 密碼強度不足
+var ErrWeakPassword = errors.New("password must be at least 8 characters and contain letters and digits")
+
+// AuthDomainService 身份驗證領域服務
+type AuthDomainService struct {
+	// dummyHash 用戶不存在時仍執行一次 bcrypt 比對，使回應時間與密碼錯誤一致，避免以時間差探測帳號
+	dummyHash string
+}
+
+// NewAuthDomainService 創建身份驗證領域服務
+func NewAuthDomainService() (*AuthDomainService, error) {
+	dummyHash, err := crypto.HashPassword("dummy-password-for-timing")
+	if err != nil {
+		return nil, err
+	}
+	return &AuthDomainService{dummyHash: dummyHash}, nil
+}
+
+// ValidatePassword 檢查密碼強度
+func (s *AuthDomainService) ValidatePassword(password string) error {
+	if len(password) < minPasswordLength {
+		return ErrWeakPassword
+	}
+
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	if !hasLetter || !hasDigit {
+		return ErrWeakPassword
+	}
+	return nil
+}
+
+// HashPassword 檢查強度並加密密碼
+func (s *AuthDomainService) HashPassword(password string) (string, error) {
+	if err := s.ValidatePassword(password); err != nil {
+		return "", err
+	}
+	return crypto.HashPassword(password)
+}
+
+// VerifyPassword 驗證密碼，user 為 nil（用戶不存在）時以假雜湊比對後返回 false
+func (s *AuthDomainService) VerifyPassword(user *entity.User, password string) bool {
+	if user == nil {
+		crypto.CheckPassword(password, s.dummyHash)
+		return false
+	}
+	return crypto.CheckPassword(password, user.PasswordHash)
+}
@@ -0,0 +1,582 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"path"
+	"strconv"
+
+	"sync_drive_backend/internal/common/middleware/auth"
+	"sync_drive_backend/internal/common/middleware/request"
+	"sync_drive_backend/internal/core/auth/application"
+	"sync_drive_backend/internal/core/auth/application/dto"
+	"sync_drive_backend/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// oidcStateCookie 保存 OIDC state 綁定值的 Cookie 名稱
+const oidcStateCookie = "oidc_state"
+
+// Controller Auth Controller
+type Controller struct {
+	authService         *application.AuthService
+	tokenService        *application.TokenService
+	verificationService *application.VerificationService
+	mfaService          *application.MFAService
+	loginGuard          *application.LoginGuard
+	ssoService          *application.SSOService
+	permissionService   *application.PermissionService
+}
+
+// NewController 創建 Auth Controller
+func NewController(
+	authService *application.AuthService,
+	tokenService *application.TokenService,
+	verificationService *application.VerificationService,
+	mfaService *application.MFAService,
+	loginGuard *application.LoginGuard,
+	ssoService *application.SSOService,
+	permissionService *application.PermissionService,
+) *Controller {
+	return &Controller{
+		authService:         authService,
+		tokenService:        tokenService,
+		verificationService: verificationService,
+		mfaService:          mfaService,
+		loginGuard:          loginGuard,
+		ssoService:          ssoService,
+		permissionService:   permissionService,
+	}
+}
+
+// Register 註冊
+// POST /api/v1/auth/register
+func (ctl *Controller) Register(c *gin.Context) {
+	var req dto.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Wrap(errors.ErrInvalidParams, "invalid request body", err))
+		return
+	}
+
+	resp, err := ctl.authService.Register(c.Request.Context(), &req, clientInfo(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, resp)
+}
+
+// Login 登入
+// POST /api/v1/auth/login
+func (ctl *Controller) Login(c *gin.Context) {
+	var req dto.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Wrap(errors.ErrInvalidParams, "invalid request body", err))
+		return
+	}
+
+	resp, err := ctl.authService.Login(c.Request.Context(), &req, clientInfo(c))
+	if err != nil {
+		handleLoginError(c, err)
+		return
+	}
+	errors.Success(c, resp)
+}
+
+// Refresh 換發 Token
+// POST /api/v1/auth/refresh
+func (ctl *Controller) Refresh(c *gin.Context) {
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Wrap(errors.ErrInvalidParams, "invalid request body", err))
+		return
+	}
+
+	tokens, err := ctl.tokenService.Refresh(c.Request.Context(), req.RefreshToken, clientInfo(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, tokens)
+}
+
+// Logout 登出
+// POST /api/v1/auth/logout
+func (ctl *Controller) Logout(c *gin.Context) {
+	var req dto.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Wrap(errors.ErrInvalidParams, "invalid request body", err))
+		return
+	}
+
+	if err := ctl.tokenService.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, nil)
+}
+
+// GetProfile 取得個人資料
+// GET /api/v1/auth/me
+func (ctl *Controller) GetProfile(c *gin.Context) {
+	profile, err := ctl.authService.GetProfile(c.Request.Context(), auth.GetUserID(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, profile)
+}
+
+// UpdateProfile 更新個人資料
+// PUT /api/v1/auth/me
+func (ctl *Controller) UpdateProfile(c *gin.Context) {
+	var req dto.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Wrap(errors.ErrInvalidParams, "invalid request body", err))
+		return
+	}
+
+	profile, err := ctl.authService.UpdateProfile(c.Request.Context(), auth.GetUserID(c), &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, profile)
+}
+
+// ChangePassword 變更密碼，返回目前裝置的新 Token
+// PUT /api/v1/auth/me/password
+func (ctl *Controller) ChangePassword(c *gin.Context) {
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Wrap(errors.ErrInvalidParams, "invalid request body", err))
+		return
+	}
+
+	tokens, err := ctl.authService.ChangePassword(c.Request.Context(), auth.GetUserID(c), &req, clientInfo(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, tokens)
+}
+
+// SendEmailVerification 重新寄送 Email 驗證信
+// POST /api/v1/auth/me/verify-email
+func (ctl *Controller) SendEmailVerification(c *gin.Context) {
+	if err := ctl.verificationService.SendEmailVerification(c.Request.Context(), auth.GetUserID(c), clientInfo(c).Locale); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, nil)
+}
+
+// VerifyEmail 驗證 Email
+// POST /api/v1/auth/verify-email
+func (ctl *Controller) VerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Wrap(errors.ErrInvalidParams, "invalid request body", err))
+		return
+	}
+
+	if err := ctl.verificationService.VerifyEmail(c.Request.Context(), &req); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, nil)
+}
+
+// ForgotPassword 寄送重設密碼信，無論 Email 是否已註冊都返回成功
+// POST /api/v1/auth/forgot-password
+func (ctl *Controller) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Wrap(errors.ErrInvalidParams, "invalid request body", err))
+		return
+	}
+
+	if err := ctl.verificationService.ForgotPassword(c.Request.Context(), &req, clientInfo(c).Locale); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, nil)
+}
+
+// ResetPassword 重設密碼，所有裝置需重新登入
+// POST /api/v1/auth/reset-password
+func (ctl *Controller) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Wrap(errors.ErrInvalidParams, "invalid request body", err))
+		return
+	}
+
+	if err := ctl.verificationService.ResetPassword(c.Request.Context(), &req); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, nil)
+}
+
+// VerifyMFA MFA 登入第二步
+// POST /api/v1/auth/mfa/verify
+func (ctl *Controller) VerifyMFA(c *gin.Context) {
+	var req dto.MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Wrap(errors.ErrInvalidParams, "invalid request body", err))
+		return
+	}
+
+	resp, err := ctl.mfaService.Verify(c.Request.Context(), &req, clientInfo(c))
+	if err != nil {
+		handleLoginError(c, err)
+		return
+	}
+	errors.Success(c, resp)
+}
+
+// EnrollMFAWithChallenge 強制 MFA 的角色登入時綁定 TOTP
+// POST /api/v1/auth/mfa/enroll
+func (ctl *Controller) EnrollMFAWithChallenge(c *gin.Context) {
+	var req dto.MFAEnrollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Wrap(errors.ErrInvalidParams, "invalid request body", err))
+		return
+	}
+
+	resp, err := ctl.mfaService.EnrollWithChallenge(c.Request.Context(), &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, resp)
+}
+
+// EnrollMFA 開始綁定 TOTP
+// POST /api/v1/auth/me/mfa/enroll
+func (ctl *Controller) EnrollMFA(c *gin.Context) {
+	resp, err := ctl.mfaService.BeginEnrollment(c.Request.Context(), auth.GetUserID(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, resp)
+}
+
+// ConfirmMFA 確認綁定 TOTP，返回復原碼
+// POST /api/v1/auth/me/mfa/confirm
+func (ctl *Controller) ConfirmMFA(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Wrap(errors.ErrInvalidParams, "invalid request body", err))
+		return
+	}
+
+	resp, err := ctl.mfaService.ConfirmEnrollment(c.Request.Context(), auth.GetUserID(c), &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, resp)
+}
+
+// RegenerateRecoveryCodes 重新產生復原碼
+// POST /api/v1/auth/me/mfa/recovery-codes
+func (ctl *Controller) RegenerateRecoveryCodes(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Wrap(errors.ErrInvalidParams, "invalid request body", err))
+		return
+	}
+
+	resp, err := ctl.mfaService.RegenerateRecoveryCodes(c.Request.Context(), auth.GetUserID(c), &req, clientInfo(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, resp)
+}
+
+// DisableMFA 解除 TOTP 綁定
+// DELETE /api/v1/auth/me/mfa
+func (ctl *Controller) DisableMFA(c *gin.Context) {
+	var req dto.DisableMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Wrap(errors.ErrInvalidParams, "invalid request body", err))
+		return
+	}
+
+	if err := ctl.mfaService.Disable(c.Request.Context(), auth.GetUserID(c), &req, clientInfo(c)); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, nil)
+}
+
+// OIDCAuthorize 取得 OIDC 授權網址，並將 state 的綁定值寫入 Cookie
+// GET /api/v1/auth/oidc/authorize
+func (ctl *Controller) OIDCAuthorize(c *gin.Context) {
+	resp, err := ctl.ssoService.Authorize(c.Request.Context())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	setOIDCStateCookie(c, resp.Binding, 0)
+	errors.Success(c, resp)
+}
+
+// OIDCCallback 完成 OIDC 登入
+// POST /api/v1/auth/oidc/callback
+func (ctl *Controller) OIDCCallback(c *gin.Context) {
+	var req dto.OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Wrap(errors.ErrInvalidParams, "invalid request body", err))
+		return
+	}
+	// Cookie 只用於本次回呼，讀取後即清除
+	req.Binding, _ = c.Cookie(oidcStateCookie)
+	setOIDCStateCookie(c, "", -1)
+
+	resp, err := ctl.ssoService.Callback(c.Request.Context(), &req, clientInfo(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, resp)
+}
+
+// UnlockUser 解除帳號的登入鎖定（管理員）
+// POST /api/v1/auth/admin/users/:id/unlock
+func (ctl *Controller) UnlockUser(c *gin.Context) {
+	if err := ctl.loginGuard.UnlockUser(c.Request.Context(), c.Param("id")); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, nil)
+}
+
+// UnlockIP 解除 IP 的登入鎖定（管理員）
+// POST /api/v1/auth/admin/ips/:ip/unlock
+func (ctl *Controller) UnlockIP(c *gin.Context) {
+	ip := net.ParseIP(c.Param("ip"))
+	if ip == nil {
+		errors.HandleError(c, errors.New(errors.ErrInvalidParams, "invalid ip"))
+		return
+	}
+
+	if err := ctl.loginGuard.UnlockIP(c.Request.Context(), ip.String()); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, nil)
+}
+
+// AssignUserRole 指派用戶角色（管理員）
+// PUT /api/v1/auth/admin/users/:id/role
+func (ctl *Controller) AssignUserRole(c *gin.Context) {
+	var req dto.AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Wrap(errors.ErrInvalidParams, "invalid request body", err))
+		return
+	}
+
+	resp, err := ctl.permissionService.AssignUserRole(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, resp)
+}
+
+// ListRoles 列出全部角色（管理員）
+// GET /api/v1/auth/admin/roles
+func (ctl *Controller) ListRoles(c *gin.Context) {
+	resp, err := ctl.permissionService.ListRoles(c.Request.Context())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, resp)
+}
+
+// GetRole 查詢角色（管理員）
+// GET /api/v1/auth/admin/roles/:name
+func (ctl *Controller) GetRole(c *gin.Context) {
+	resp, err := ctl.permissionService.GetRole(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, resp)
+}
+
+// CreateRole 建立角色（管理員）
+// POST /api/v1/auth/admin/roles
+func (ctl *Controller) CreateRole(c *gin.Context) {
+	var req dto.CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Wrap(errors.ErrInvalidParams, "invalid request body", err))
+		return
+	}
+
+	resp, err := ctl.permissionService.CreateRole(c.Request.Context(), &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, resp)
+}
+
+// UpdateRole 更新角色說明（管理員）
+// PUT /api/v1/auth/admin/roles/:name
+func (ctl *Controller) UpdateRole(c *gin.Context) {
+	var req dto.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Wrap(errors.ErrInvalidParams, "invalid request body", err))
+		return
+	}
+
+	resp, err := ctl.permissionService.UpdateRole(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, resp)
+}
+
+// DeleteRole 刪除角色（管理員）
+// DELETE /api/v1/auth/admin/roles/:name
+func (ctl *Controller) DeleteRole(c *gin.Context) {
+	if err := ctl.permissionService.DeleteRole(c.Request.Context(), c.Param("name")); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, nil)
+}
+
+// SetRolePermissions 設定角色權限（管理員）
+// PUT /api/v1/auth/admin/roles/:name/permissions
+func (ctl *Controller) SetRolePermissions(c *gin.Context) {
+	var req dto.SetRolePermissionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Wrap(errors.ErrInvalidParams, "invalid request body", err))
+		return
+	}
+
+	resp, err := ctl.permissionService.SetRolePermissions(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, resp)
+}
+
+// ListPermissions 列出全部權限（管理員）
+// GET /api/v1/auth/admin/permissions
+func (ctl *Controller) ListPermissions(c *gin.Context) {
+	resp, err := ctl.permissionService.ListPermissions(c.Request.Context())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, resp)
+}
+
+// CreatePermission 建立權限（管理員）
+// POST /api/v1/auth/admin/permissions
+func (ctl *Controller) CreatePermission(c *gin.Context) {
+	var req dto.CreatePermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Wrap(errors.ErrInvalidParams, "invalid request body", err))
+		return
+	}
+
+	resp, err := ctl.permissionService.CreatePermission(c.Request.Context(), &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, resp)
+}
+
+// DeletePermission 刪除權限（管理員）
+// DELETE /api/v1/auth/admin/permissions/:code
+func (ctl *Controller) DeletePermission(c *gin.Context) {
+	if err := ctl.permissionService.DeletePermission(c.Request.Context(), c.Param("code")); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, nil)
+}
+
+// JWKS 公開的驗證金鑰
+// GET /.well-known/jwks.json
+func (ctl *Controller) JWKS(c *gin.Context) {
+	// 金鑰輪替時新增的公鑰需在切換簽發金鑰前被驗證端取得，快取時間應遠短於輪替間隔
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, ctl.tokenService.JWKS())
+}
+
+// ListSessions 列出目前用戶的登入 Session
+// GET /api/v1/auth/sessions
+func (ctl *Controller) ListSessions(c *gin.Context) {
+	sessions, err := ctl.tokenService.ListSessions(c.Request.Context(), auth.GetUserID(c), auth.GetSessionID(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, sessions)
+}
+
+// RevokeSession 撤銷指定的登入 Session
+// DELETE /api/v1/auth/sessions/:id
+func (ctl *Controller) RevokeSession(c *gin.Context) {
+	if err := ctl.tokenService.RevokeSession(c.Request.Context(), auth.GetUserID(c), c.Param("id")); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, nil)
+}
+
+// RevokeAllSessions 登出所有裝置
+// DELETE /api/v1/auth/sessions
+func (ctl *Controller) RevokeAllSessions(c *gin.Context) {
+	if err := ctl.tokenService.RevokeAll(c.Request.Context(), auth.GetUserID(c)); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, nil)
+}
+
+// handleLoginError 登入失敗過於頻繁時回應 Retry-After
+func handleLoginError(c *gin.Context, err error) {
+	if throttled, ok := application.AsLoginThrottled(err); ok {
+		c.Header("Retry-After", strconv.Itoa(throttled.RetryAfterSeconds()))
+	}
+	errors.HandleError(c, err)
+}
+
+// setOIDCStateCookie 設定 OIDC state 綁定 Cookie，僅限 OIDC 路由使用，maxAge < 0 表示刪除
+func setOIDCStateCookie(c *gin.Context, value string, maxAge int) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    value,
+		Path:     path.Dir(c.FullPath()),
+		MaxAge:   maxAge,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// clientInfo 取得客戶端資訊
+func clientInfo(c *gin.Context) *dto.ClientInfo {
+	return &dto.ClientInfo{
+		DeviceID:  c.GetHeader(request.HeaderDeviceID),
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.ClientIP(),
+		Locale:    c.GetHeader("Accept-Language"),
+	}
+}
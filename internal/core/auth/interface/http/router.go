@@ -0,0 +1,71 @@
+package http
+
+import (
+	"sync_drive_backend/internal/common/consts"
+	"sync_drive_backend/internal/common/middleware/auth"
+	"sync_drive_backend/internal/common/middleware/request"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes 註冊路由（/api/v1/auth），authRequired 為 JWT 驗證中介層
+// 回應含 Token、MFA 密鑰或復原碼的端點標記 no-store，不被快取也不被冪等中介層保存
+func (ctl *Controller) RegisterRoutes(rg *gin.RouterGroup, authRequired gin.HandlerFunc, authz *auth.Authorizer) {
+	noStore := request.NoStore()
+
+	rg.POST("/register", noStore, ctl.Register)
+	rg.POST("/login", noStore, ctl.Login)
+	rg.POST("/refresh", noStore, ctl.Refresh)
+	rg.POST("/logout", ctl.Logout)
+	rg.POST("/forgot-password", ctl.ForgotPassword)
+	rg.POST("/reset-password", ctl.ResetPassword)
+	rg.POST("/verify-email", ctl.VerifyEmail)
+	rg.POST("/mfa/verify", noStore, ctl.VerifyMFA)
+	rg.POST("/mfa/enroll", noStore, ctl.EnrollMFAWithChallenge)
+	rg.GET("/oidc/authorize", noStore, ctl.OIDCAuthorize)
+	rg.POST("/oidc/callback", noStore, ctl.OIDCCallback)
+
+	me := rg.Group("/me", authRequired)
+	{
+		me.GET("", ctl.GetProfile)
+		me.PUT("", ctl.UpdateProfile)
+		me.PUT("/password", ctl.ChangePassword)
+		me.POST("/verify-email", ctl.SendEmailVerification)
+		me.POST("/mfa/enroll", noStore, ctl.EnrollMFA)
+		me.POST("/mfa/confirm", noStore, ctl.ConfirmMFA)
+		me.POST("/mfa/recovery-codes", noStore, ctl.RegenerateRecoveryCodes)
+		me.DELETE("/mfa", ctl.DisableMFA)
+	}
+
+	sessions := rg.Group("/sessions", authRequired)
+	{
+		sessions.GET("", ctl.ListSessions)
+		sessions.DELETE("", ctl.RevokeAllSessions)
+		sessions.DELETE("/:id", ctl.RevokeSession)
+	}
+
+	users := rg.Group("/admin", authRequired, authz.RequirePermission(consts.PermissionUserManage))
+	{
+		users.POST("/users/:id/unlock", ctl.UnlockUser)
+		users.POST("/ips/:ip/unlock", ctl.UnlockIP)
+		users.PUT("/users/:id/role", ctl.AssignUserRole)
+	}
+
+	roles := rg.Group("/admin", authRequired, authz.RequirePermission(consts.PermissionRoleManage))
+	{
+		roles.GET("/roles", ctl.ListRoles)
+		roles.POST("/roles", ctl.CreateRole)
+		roles.GET("/roles/:name", ctl.GetRole)
+		roles.PUT("/roles/:name", ctl.UpdateRole)
+		roles.DELETE("/roles/:name", ctl.DeleteRole)
+		roles.PUT("/roles/:name/permissions", ctl.SetRolePermissions)
+		roles.GET("/permissions", ctl.ListPermissions)
+		roles.POST("/permissions", ctl.CreatePermission)
+		roles.DELETE("/permissions/:code", ctl.DeletePermission)
+	}
+}
+
+// RegisterWellKnown 註冊公開的 /.well-known 端點
+func (ctl *Controller) RegisterWellKnown(router gin.IRouter) {
+	router.GET("/.well-known/jwks.json", ctl.JWKS)
+}
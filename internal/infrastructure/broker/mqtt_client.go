@@ -0,0 +1,45 @@
+package broker
+
+import (
+	"fmt"
+	"time"
+
+	"sync_drive_backend/pkg/logger"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"go.uber.org/zap"
+)
+
+// Config MQTT 配置
+type Config struct {
+	Broker   string
+	ClientID string
+	Username string
+	Password string
+	QoS      byte
+}
+
+// Init 初始化 MQTT 連接
+// 啟用自動重連，Broker 暫時無法連線時不阻擋服務啟動
+func Init(cfg *Config) (mqtt.Client, error) {
+	opts := mqtt.NewClientOptions().
+		AddBroker(cfg.Broker).
+		SetClientID(cfg.ClientID).
+		SetUsername(cfg.Username).
+		SetPassword(cfg.Password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(5 * time.Second).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			logger.Warn("MQTT connection lost", zap.Error(err))
+		})
+
+	client := mqtt.NewClient(opts)
+
+	token := client.Connect()
+	if token.WaitTimeout(5*time.Second) && token.Error() != nil {
+		return nil, fmt.Errorf("failed to connect mqtt: %w", token.Error())
+	}
+
+	return client, nil
+}
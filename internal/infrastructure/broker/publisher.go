@@ -0,0 +1,76 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"sync_drive_backend/internal/infrastructure/event"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/redis/go-redis/v9"
+)
+
+// MQTTPublisher 以 MQTT 發佈事件訊息
+type MQTTPublisher struct {
+	client mqtt.Client
+	qos    byte
+}
+
+// 確保實作介面
+var _ event.Publisher = (*MQTTPublisher)(nil)
+
+// NewMQTTPublisher 創建 MQTT 發佈者
+func NewMQTTPublisher(client mqtt.Client, qos byte) *MQTTPublisher {
+	return &MQTTPublisher{client: client, qos: qos}
+}
+
+// Publish 發佈訊息，等待 Broker 確認（QoS > 0）或 context 結束
+func (p *MQTTPublisher) Publish(ctx context.Context, topic string, msg *event.Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	token := p.client.Publish(topic, p.qos, false, body)
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RedisPublisher 以 Redis Stream 發佈事件訊息
+// 每個 topic 對應一個 stream，欄位 id 為訊息 ID、data 為完整訊息 JSON
+type RedisPublisher struct {
+	client redis.UniversalClient
+	maxLen int64
+}
+
+// 確保實作介面
+var _ event.Publisher = (*RedisPublisher)(nil)
+
+// NewRedisPublisher 創建 Redis 發佈者
+// maxLen: stream 保留的大約訊息數量上限（0 表示不限制）
+func NewRedisPublisher(client redis.UniversalClient, maxLen int64) *RedisPublisher {
+	return &RedisPublisher{client: client, maxLen: maxLen}
+}
+
+// Publish 發佈訊息
+func (p *RedisPublisher) Publish(ctx context.Context, topic string, msg *event.Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	return p.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: topic,
+		MaxLen: p.maxLen,
+		Approx: p.maxLen > 0,
+		Values: map[string]interface{}{
+			"id":   msg.ID,
+			"data": body,
+		},
+	}).Err()
+}
@@ -0,0 +1,28 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Event 待發佈的領域事件
+type Event struct {
+	Topic   string      // 發佈主題（MQTT topic 或 Redis stream）
+	Type    string      // 事件類型，例如：order.assigned
+	Payload interface{} // 事件內容，將以 JSON 序列化
+}
+
+// Message 實際發佈的訊息格式
+// ID 在重試時保持不變，消費端可據此去重（at-least-once）
+type Message struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Payload    json.RawMessage `json:"payload"`
+	OccurredAt time.Time       `json:"occurredAt"`
+}
+
+// Publisher 訊息發佈者介面
+type Publisher interface {
+	Publish(ctx context.Context, topic string, msg *Message) error
+}
@@ -0,0 +1,62 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"sync_drive_backend/internal/infrastructure/persistence/mysql/record"
+	"sync_drive_backend/pkg/tools"
+
+	"gorm.io/gorm"
+)
+
+// Outbox 交易式 Outbox 寫入器
+// 事件與業務資料寫在同一個交易中，交易提交後才會由 Relay 發佈
+type Outbox struct {
+	db *gorm.DB
+}
+
+// NewOutbox 創建 Outbox 寫入器
+func NewOutbox(db *gorm.DB) *Outbox {
+	return &Outbox{db: db}
+}
+
+// Add 將事件寫入 Outbox，返回訊息 ID
+// tx 為業務交易，傳入 nil 時使用獨立連線寫入（不保證與業務資料一致）
+//
+//	db.Transaction(func(tx *gorm.DB) error {
+//		if err := tx.Save(order).Error; err != nil {
+//			return err
+//		}
+//		_, err := outbox.Add(ctx, tx, &event.Event{Topic: "order/assigned", Type: "order.assigned", Payload: order})
+//		return err
+//	})
+func (o *Outbox) Add(ctx context.Context, tx *gorm.DB, evt *Event) (string, error) {
+	if tx == nil {
+		tx = o.db
+	}
+
+	payload, err := json.Marshal(evt.Payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal event payload: %w", err)
+	}
+
+	now := time.Now()
+	rec := &record.OutboxMessage{
+		MessageID:   tools.GenerateUUID(),
+		Topic:       evt.Topic,
+		EventType:   evt.Type,
+		Payload:     string(payload),
+		Status:      record.OutboxStatusPending,
+		AvailableAt: now,
+		CreatedAt:   now,
+	}
+
+	if err := tx.WithContext(ctx).Create(rec).Error; err != nil {
+		return "", fmt.Errorf("failed to write outbox message: %w", err)
+	}
+
+	return rec.MessageID, nil
+}
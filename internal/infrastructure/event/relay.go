@@ -0,0 +1,251 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"sync_drive_backend/internal/common/util"
+	"sync_drive_backend/internal/infrastructure/persistence/mysql/record"
+	"sync_drive_backend/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RelayConfig Outbox Relay 配置
+type RelayConfig struct {
+	PollInterval   time.Duration // 輪詢間隔
+	PublishTimeout time.Duration // 單筆發佈的逾時時間，Broker 無回應時放棄並排程重試
+	BatchSize      int           // 每次取出的訊息數量
+	MaxAttempts    int           // 最大發佈次數，超過後標記為 failed
+	RetryBackoff   time.Duration // 重試基礎退避時間（指數成長）
+	MaxBackoff     time.Duration // 重試退避上限
+	Retention      time.Duration // 已發佈訊息保留時間
+	PruneInterval  time.Duration // 清理間隔
+}
+
+// Relay Outbox 背景發佈器
+// 以 SELECT ... FOR UPDATE SKIP LOCKED 認領待發佈訊息，多個副本可同時運行
+type Relay struct {
+	db        *gorm.DB
+	publisher Publisher
+	cfg       *RelayConfig
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewRelay 創建 Outbox Relay
+func NewRelay(db *gorm.DB, publisher Publisher, cfg *RelayConfig) *Relay {
+	return &Relay{
+		db:        db,
+		publisher: publisher,
+		cfg:       cfg,
+	}
+}
+
+// Start 啟動背景發佈
+func (r *Relay) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go r.run(ctx)
+}
+
+// Stop 停止背景發佈，等待進行中的批次完成
+func (r *Relay) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel = nil
+	r.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run 輪詢並發佈訊息，定期清理已發佈的舊訊息
+func (r *Relay) run(ctx context.Context) {
+	defer close(r.done)
+
+	pollTicker := time.NewTicker(r.cfg.PollInterval)
+	defer pollTicker.Stop()
+
+	pruneTicker := time.NewTicker(r.cfg.PruneInterval)
+	defer pruneTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-pollTicker.C:
+			r.drain(ctx)
+		case <-pruneTicker.C:
+			if err := r.prune(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error("Failed to prune outbox messages", zap.Error(err))
+			}
+		}
+	}
+}
+
+// drain 持續發佈直到沒有滿批的待發佈訊息
+func (r *Relay) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		n, err := r.dispatch(ctx)
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				logger.Error("Failed to dispatch outbox messages", zap.Error(err))
+			}
+			return
+		}
+		if n < r.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// dispatch 認領一批待發佈訊息並逐筆發佈，返回處理筆數
+// 認領時以 SKIP LOCKED 鎖定並將 available_at 延後作為租約後立即提交，發佈在交易外進行，
+// Broker 無回應時不會長時間持有資料列鎖；Relay 中斷時訊息於租約到期後由其他副本重新發佈（消費端以訊息 ID 去重）
+func (r *Relay) dispatch(ctx context.Context) (int, error) {
+	messages, err := r.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range messages {
+		if err := r.publish(ctx, &messages[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(messages), nil
+}
+
+// claim 認領一批待發佈訊息，租約涵蓋整批逐筆發佈的最長時間
+func (r *Relay) claim(ctx context.Context) ([]record.OutboxMessage, error) {
+	var messages []record.OutboxMessage
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND available_at <= ?", record.OutboxStatusPending, now).
+			Order("id").
+			Limit(r.cfg.BatchSize).
+			Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+
+		ids := make([]uint64, 0, len(messages))
+		for i := range messages {
+			ids = append(ids, messages[i].ID)
+		}
+		leaseUntil := now.Add(r.cfg.PublishTimeout * time.Duration(len(messages)+1))
+		return tx.Model(&record.OutboxMessage{}).Where("id IN ?", ids).Update("available_at", leaseUntil).Error
+	})
+
+	return messages, err
+}
+
+// publish 發佈單筆訊息並更新狀態
+// 發佈失敗僅記錄並排程重試，不中斷整批
+func (r *Relay) publish(ctx context.Context, msg *record.OutboxMessage) error {
+	attempts := msg.Attempts + 1
+
+	pubCtx, cancel := context.WithTimeout(ctx, r.cfg.PublishTimeout)
+	pubErr := r.publisher.Publish(pubCtx, msg.Topic, &Message{
+		ID:         msg.MessageID,
+		Type:       msg.EventType,
+		Payload:    json.RawMessage(msg.Payload),
+		OccurredAt: msg.CreatedAt,
+	})
+	cancel()
+	if pubErr != nil && ctx.Err() != nil {
+		// 停止中，保留租約，到期後重新發佈
+		return ctx.Err()
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{"attempts": attempts}
+	if pubErr == nil {
+		updates["status"] = record.OutboxStatusSent
+		updates["sent_at"] = now
+		updates["last_error"] = ""
+	} else {
+		updates["last_error"] = util.Truncate(pubErr.Error(), 1024)
+		if attempts >= r.cfg.MaxAttempts {
+			updates["status"] = record.OutboxStatusFailed
+			logger.Error("Outbox message exceeded max attempts",
+				zap.String("message_id", msg.MessageID),
+				zap.String("topic", msg.Topic),
+				zap.Error(pubErr),
+			)
+		} else {
+			updates["available_at"] = now.Add(r.backoff(attempts))
+			logger.Warn("Failed to publish outbox message, will retry",
+				zap.String("message_id", msg.MessageID),
+				zap.String("topic", msg.Topic),
+				zap.Int("attempts", attempts),
+				zap.Error(pubErr),
+			)
+		}
+	}
+
+	// 已發佈的訊息即使在停止時也要記錄，避免重複發佈
+	return r.db.WithContext(context.WithoutCancel(ctx)).Model(&record.OutboxMessage{}).
+		Where("id = ? AND status = ?", msg.ID, record.OutboxStatusPending).
+		Updates(updates).Error
+}
+
+// backoff 計算第 n 次失敗後的退避時間
+func (r *Relay) backoff(attempts int) time.Duration {
+	d := r.cfg.RetryBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= r.cfg.MaxBackoff {
+			return r.cfg.MaxBackoff
+		}
+	}
+	return d
+}
+
+// prune 分批刪除超過保留時間的已發佈訊息
+func (r *Relay) prune(ctx context.Context) error {
+	cutoff := time.Now().Add(-r.cfg.Retention)
+
+	for ctx.Err() == nil {
+		result := r.db.WithContext(ctx).Exec(
+			"DELETE FROM outbox_messages WHERE status = ? AND sent_at < ? LIMIT ?",
+			record.OutboxStatusSent, cutoff, r.cfg.BatchSize,
+		)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected < int64(r.cfg.BatchSize) {
+			return nil
+		}
+	}
+
+	return ctx.Err()
+}
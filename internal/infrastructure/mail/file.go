@@ -0,0 +1,86 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"sync_drive_backend/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// FileMailer 將郵件寫入 .eml 檔案，可直接以郵件軟體開啟檢視（本地開發）
+type FileMailer struct {
+	dir  string
+	from *mail.Address
+}
+
+// NewFileMailer 創建 File Mailer
+func NewFileMailer(dir string, from *mail.Address) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &FileMailer{
+		dir:  dir,
+		from: from,
+	}, nil
+}
+
+// Send 寫入檔案
+func (m *FileMailer) Send(_ context.Context, msg *Message) error {
+	data, err := build(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405.000000000"), sanitizeFilename(msg.To[0]))
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write mail file: %w", err)
+	}
+
+	logger.Info("Mail written to file",
+		zap.Strings("to", msg.To),
+		zap.String("subject", msg.Subject),
+		zap.String("path", path),
+	)
+	return nil
+}
+
+// LogMailer 僅將郵件內容記錄於日誌（測試環境）
+type LogMailer struct {
+	from *mail.Address
+}
+
+// NewLogMailer 創建 Log Mailer
+func NewLogMailer(from *mail.Address) *LogMailer {
+	return &LogMailer{from: from}
+}
+
+// Send 記錄日誌
+func (m *LogMailer) Send(_ context.Context, msg *Message) error {
+	logger.Info("Mail sent (log driver)",
+		zap.String("from", m.from.String()),
+		zap.Strings("to", msg.To),
+		zap.String("subject", msg.Subject),
+		zap.String("text", msg.Text),
+	)
+	return nil
+}
+
+// sanitizeFilename 移除檔名中不安全的字元
+func sanitizeFilename(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_', r == '@':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
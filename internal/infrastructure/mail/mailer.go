@@ -0,0 +1,142 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// 寄送方式
+const (
+	DriverSMTP = "smtp" // SMTP 寄送
+	DriverFile = "file" // 寫入 .eml 檔案（本地開發）
+	DriverLog  = "log"  // 僅記錄日誌（測試環境）
+)
+
+// Message 郵件內容
+type Message struct {
+	To      []string `json:"to"`
+	Subject string   `json:"subject"`
+	Text    string   `json:"text"`
+	HTML    string   `json:"html,omitempty"`
+}
+
+// Mailer 郵件寄送
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Config 郵件配置
+type Config struct {
+	Driver        string // smtp, file, log
+	From          string // 寄件者，例如：Sync Drive <no-reply@example.com>
+	FileDir       string // file driver 的輸出目錄
+	DefaultLocale string // 範本預設語系
+	SMTP          SMTPConfig
+}
+
+// New 依 driver 創建 Mailer
+func New(cfg *Config) (Mailer, error) {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mail.from: %w", err)
+	}
+
+	switch cfg.Driver {
+	case DriverSMTP:
+		return NewSMTPMailer(&cfg.SMTP, from), nil
+	case DriverFile:
+		return NewFileMailer(cfg.FileDir, from)
+	case DriverLog:
+		return NewLogMailer(from), nil
+	default:
+		return nil, fmt.Errorf("unsupported mail driver %q", cfg.Driver)
+	}
+}
+
+// build 組成 MIME 郵件（multipart/alternative，純文字與 HTML）
+func build(from *mail.Address, msg *Message) ([]byte, error) {
+	if len(msg.To) == 0 {
+		return nil, fmt.Errorf("mail: no recipients")
+	}
+
+	to := make([]string, 0, len(msg.To))
+	for _, rcpt := range msg.To {
+		addr, err := mail.ParseAddress(rcpt)
+		if err != nil {
+			return nil, fmt.Errorf("mail: invalid recipient %q: %w", rcpt, err)
+		}
+		to = append(to, addr.String())
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	header := []string{
+		"From: " + from.String(),
+		"To: " + strings.Join(to, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", stripNewlines(msg.Subject)),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: " + messageID(from),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + mw.Boundary(),
+	}
+	buf.WriteString(strings.Join(header, "\r\n") + "\r\n\r\n")
+
+	if err := writePart(mw, "text/plain", msg.Text); err != nil {
+		return nil, err
+	}
+	if msg.HTML != "" {
+		if err := writePart(mw, "text/html", msg.HTML); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writePart 寫入 quoted-printable 編碼的內容
+func writePart(mw *multipart.Writer, contentType, body string) error {
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType + "; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// messageID 產生 Message-ID
+func messageID(from *mail.Address) string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	domain := "localhost"
+	if at := strings.LastIndex(from.Address, "@"); at >= 0 {
+		domain = from.Address[at+1:]
+	}
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
+
+// stripNewlines 移除換行，避免 header injection
+func stripNewlines(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
@@ -0,0 +1,29 @@
+package mail
+
+import (
+	"context"
+
+	"sync_drive_backend/internal/infrastructure/queue"
+)
+
+// SendJob 寄送郵件的背景工作
+var SendJob = queue.NewJobType[Message]("notifications", "mail.send")
+
+// QueuedMailer 將郵件加入工作佇列，由背景工作寄送（失敗依佇列設定重試）
+type QueuedMailer struct {
+	queue *queue.Queue
+}
+
+// NewQueuedMailer 創建 Queued Mailer，並註冊以 delivery 實際寄送的處理函數
+func NewQueuedMailer(q *queue.Queue, delivery Mailer) *QueuedMailer {
+	queue.Handle(q, SendJob, func(ctx context.Context, msg Message) error {
+		return delivery.Send(ctx, &msg)
+	})
+	return &QueuedMailer{queue: q}
+}
+
+// Send 加入工作佇列
+func (m *QueuedMailer) Send(ctx context.Context, msg *Message) error {
+	_, err := queue.Enqueue(ctx, m.queue, SendJob, *msg, queue.WithPriority(queue.PriorityHigh))
+	return err
+}
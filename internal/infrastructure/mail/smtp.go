@@ -0,0 +1,117 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTP 連線加密方式
+const (
+	SecurityNone     = "none"     // 不加密（僅限內網中繼）
+	SecurityStartTLS = "starttls" // 明文連線後升級（通常為 587 port）
+	SecurityTLS      = "tls"      // 直接以 TLS 連線（通常為 465 port）
+)
+
+// SMTPConfig SMTP 配置
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // 空值表示不驗證
+	Password string
+	Security string // none, starttls, tls
+	Timeout  time.Duration
+}
+
+// SMTPMailer 以 SMTP 寄送
+type SMTPMailer struct {
+	cfg  *SMTPConfig
+	from *mail.Address
+}
+
+// NewSMTPMailer 創建 SMTP Mailer
+func NewSMTPMailer(cfg *SMTPConfig, from *mail.Address) *SMTPMailer {
+	return &SMTPMailer{
+		cfg:  cfg,
+		from: from,
+	}
+}
+
+// Send 寄送郵件，每封郵件使用獨立連線
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	data, err := build(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	conn, err := m.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("smtp: failed to connect: %w", err)
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(m.cfg.Timeout)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp: failed to create client: %w", err)
+	}
+	defer client.Close()
+
+	if m.cfg.Security == SecurityStartTLS {
+		if err := client.StartTLS(&tls.Config{ServerName: m.cfg.Host}); err != nil {
+			return fmt.Errorf("smtp: starttls failed: %w", err)
+		}
+	}
+
+	if m.cfg.Username != "" {
+		// PlainAuth 拒絕在未加密的連線上傳送密碼（localhost 除外）
+		if err := client.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)); err != nil {
+			return fmt.Errorf("smtp: auth failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(m.from.Address); err != nil {
+		return fmt.Errorf("smtp: MAIL FROM failed: %w", err)
+	}
+	for _, rcpt := range msg.To {
+		addr, _ := mail.ParseAddress(rcpt) // build 已驗證
+		if err := client.Rcpt(addr.Address); err != nil {
+			return fmt.Errorf("smtp: RCPT TO %s failed: %w", addr.Address, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp: DATA failed: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("smtp: failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp: failed to send message: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// dial 建立連線
+func (m *SMTPMailer) dial(ctx context.Context) (net.Conn, error) {
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	dialer := &net.Dialer{Timeout: m.cfg.Timeout}
+
+	if m.cfg.Security == SecurityTLS {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: m.cfg.Host}}
+		return tlsDialer.DialContext(ctx, "tcp", addr)
+	}
+	return dialer.DialContext(ctx, "tcp", addr)
+}
@@ -0,0 +1,169 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"strings"
+	texttemplate "text/template"
+)
+
+// templatesFS 郵件範本，路徑為 templates/<locale>/<name>.txt 與 <name>.html
+// .txt 須以 {{define "subject"}} 定義主旨；.html 可省略，省略時僅寄送純文字
+//
+//go:embed templates
+var templatesFS embed.FS
+
+// templateSet 單一範本（某語系）
+type templateSet struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// Renderer 郵件範本渲染
+type Renderer struct {
+	defaultLocale string
+	locales       []string
+	templates     map[string]map[string]*templateSet // locale → name → 範本
+}
+
+// NewRenderer 載入內嵌的郵件範本
+func NewRenderer(defaultLocale string) (*Renderer, error) {
+	r := &Renderer{
+		defaultLocale: defaultLocale,
+		templates:     make(map[string]map[string]*templateSet),
+	}
+
+	entries, err := fs.ReadDir(templatesFS, "templates")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		locale := entry.Name()
+		if err := r.load(locale); err != nil {
+			return nil, err
+		}
+		r.locales = append(r.locales, locale)
+	}
+
+	if _, ok := r.templates[defaultLocale]; !ok {
+		return nil, fmt.Errorf("mail templates for default locale %q not found", defaultLocale)
+	}
+	return r, nil
+}
+
+// load 載入某語系的範本
+func (r *Renderer) load(locale string) error {
+	dir := "templates/" + locale
+	files, err := fs.Glob(templatesFS, dir+"/*.txt")
+	if err != nil {
+		return err
+	}
+
+	r.templates[locale] = make(map[string]*templateSet)
+	for _, file := range files {
+		name := strings.TrimSuffix(file[len(dir)+1:], ".txt")
+
+		text, err := texttemplate.ParseFS(templatesFS, file)
+		if err != nil {
+			return fmt.Errorf("failed to parse mail template %s: %w", file, err)
+		}
+		if text.Lookup("subject") == nil {
+			return fmt.Errorf("mail template %s has no subject", file)
+		}
+
+		set := &templateSet{text: text}
+		htmlFile := dir + "/" + name + ".html"
+		if _, err := fs.Stat(templatesFS, htmlFile); err == nil {
+			if set.html, err = htmltemplate.ParseFS(templatesFS, htmlFile); err != nil {
+				return fmt.Errorf("failed to parse mail template %s: %w", htmlFile, err)
+			}
+		}
+		r.templates[locale][name] = set
+	}
+	return nil
+}
+
+// Render 渲染郵件內容，locale 可為 Accept-Language 標頭，找不到對應語系時使用預設語系
+func (r *Renderer) Render(name, locale string, data any) (*Message, error) {
+	set, ok := r.templates[r.matchLocale(locale)][name]
+	if !ok {
+		if set, ok = r.templates[r.defaultLocale][name]; !ok {
+			return nil, fmt.Errorf("mail template %q not found", name)
+		}
+	}
+
+	var subject, text bytes.Buffer
+	if err := set.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, fmt.Errorf("failed to render mail subject: %w", err)
+	}
+	if err := set.text.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("failed to render mail text: %w", err)
+	}
+
+	msg := &Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+	}
+	if set.html != nil {
+		var html bytes.Buffer
+		if err := set.html.Execute(&html, data); err != nil {
+			return nil, fmt.Errorf("failed to render mail html: %w", err)
+		}
+		msg.HTML = html.String()
+	}
+	return msg, nil
+}
+
+// matchLocale 依 Accept-Language（例如：zh-TW,zh;q=0.9,en;q=0.8）選擇語系
+// 依序比對完整語系與主要語言，忽略 q 值
+func (r *Renderer) matchLocale(acceptLanguage string) string {
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		for _, locale := range r.locales {
+			if strings.EqualFold(locale, tag) {
+				return locale
+			}
+		}
+		primary := strings.SplitN(tag, "-", 2)[0]
+		for _, locale := range r.locales {
+			if strings.EqualFold(strings.SplitN(locale, "-", 2)[0], primary) {
+				return locale
+			}
+		}
+	}
+	return r.defaultLocale
+}
+
+// TemplateSender 渲染範本並寄送
+type TemplateSender struct {
+	renderer *Renderer
+	mailer   Mailer
+}
+
+// NewTemplateSender 創建範本寄送
+func NewTemplateSender(renderer *Renderer, mailer Mailer) *TemplateSender {
+	return &TemplateSender{
+		renderer: renderer,
+		mailer:   mailer,
+	}
+}
+
+// SendTemplate 以範本寄送郵件
+func (s *TemplateSender) SendTemplate(ctx context.Context, to, name, locale string, data any) error {
+	msg, err := s.renderer.Render(name, locale, data)
+	if err != nil {
+		return err
+	}
+	msg.To = []string{to}
+	return s.mailer.Send(ctx, msg)
+}
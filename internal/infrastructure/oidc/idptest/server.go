@@ -0,0 +1,174 @@
+package idptest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc/oidctest"
+)
+
+// keyID JWKS 公布的金鑰 ID
+const keyID = "test-key"
+
+// Server 測試用的 OIDC 身份提供者
+// discovery 與 JWKS 由 go-oidc 的 oidctest 提供；token endpoint 驗證 client 認證、redirect_uri 與 PKCE 後簽發 ID Token
+type Server struct {
+	*httptest.Server
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+
+	mu     sync.Mutex
+	key    *rsa.PrivateKey // JWKS 公布的金鑰
+	signer *rsa.PrivateKey // 簽發 ID Token 的金鑰
+	grants map[string]*grant
+}
+
+// grant 已核發的授權碼
+type grant struct {
+	challenge string
+	claims    map[string]any
+}
+
+// NewServer 啟動身份提供者，測試結束時自動關閉
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+
+	s := &Server{
+		ClientID:     "sync-drive",
+		ClientSecret: "client-secret",
+		RedirectURL:  "https://app.example.com/sso/callback",
+		key:          key,
+		signer:       key,
+		grants:       make(map[string]*grant),
+	}
+
+	idp := &oidctest.Server{
+		PublicKeys: []oidctest.PublicKey{{PublicKey: key.Public(), KeyID: keyID, Algorithm: "RS256"}},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", s.token)
+	mux.Handle("/", idp)
+
+	s.Server = httptest.NewServer(mux)
+	idp.SetIssuer(s.URL)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// SignWith 改以指定金鑰簽發 ID Token，模擬簽章無法以 JWKS 驗證
+func (s *Server) SignWith(key *rsa.PrivateKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.signer = key
+}
+
+// Authorize 模擬用戶於 IdP 完成登入，返回授權碼
+// 由授權網址取得 code_challenge 與 nonce；claims 覆寫 ID Token 的預設 claim（iss、aud、sub、iat、exp、nonce）
+func (s *Server) Authorize(t testing.TB, authURL string, claims map[string]any) string {
+	t.Helper()
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse authorization url: %v", err)
+	}
+	query := u.Query()
+	if query.Get("client_id") != s.ClientID || query.Get("redirect_uri") != s.RedirectURL {
+		t.Fatalf("unexpected client in authorization url: %s", authURL)
+	}
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		t.Fatalf("authorization url without S256 code challenge: %s", authURL)
+	}
+
+	now := time.Now()
+	all := map[string]any{
+		"iss":   s.URL,
+		"aud":   s.ClientID,
+		"sub":   "subject-1",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"nonce": query.Get("nonce"),
+	}
+	for k, v := range claims {
+		all[k] = v
+	}
+
+	code := rand.Text()
+	s.mu.Lock()
+	s.grants[code] = &grant{challenge: query.Get("code_challenge"), claims: all}
+	s.mu.Unlock()
+	return code
+}
+
+// token 以授權碼換取 Token，授權碼僅能使用一次
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != s.ClientID || clientSecret != s.ClientSecret {
+		tokenError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("redirect_uri") != s.RedirectURL {
+		tokenError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	s.mu.Lock()
+	g, ok := s.grants[r.PostForm.Get("code")]
+	delete(s.grants, r.PostForm.Get("code"))
+	signer := s.signer
+	s.mu.Unlock()
+	if !ok {
+		tokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != g.challenge {
+		tokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	claims, err := json.Marshal(g.claims)
+	if err != nil {
+		tokenError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"access_token": rand.Text(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     oidctest.SignIDToken(signer, keyID, "RS256", string(claims)),
+	})
+}
+
+// tokenError 回應 OAuth 2.0 錯誤
+func tokenError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
@@ -0,0 +1,161 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"sync_drive_backend/internal/core/auth/domain/entity"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// Config OIDC Relying Party 配置
+type Config struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string   // 向 IdP 註冊的 redirect_uri（前端的 callback 頁面）
+	Scopes       []string // 需包含 openid
+	GroupsClaim  string   // ID Token 中群組的 claim 名稱
+	Timeout      time.Duration
+}
+
+// Provider OIDC 身份提供者（authorization code + PKCE）
+// discovery document 於第一次使用時取得，IdP 暫時無法連線不影響服務啟動
+type Provider struct {
+	cfg    *Config
+	client *http.Client
+
+	mu       sync.Mutex
+	provider *gooidc.Provider
+	verifier *gooidc.IDTokenVerifier
+	oauth    *oauth2.Config
+}
+
+// NewProvider 創建 OIDC 身份提供者
+func NewProvider(cfg *Config) *Provider {
+	return &Provider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// AuthCodeURL 產生授權網址，nonce 寫入 ID Token 防止重放，code_verifier 以 S256 challenge 送出
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	oauth, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	return oauth.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier)), nil
+}
+
+// Exchange 以授權碼換取 Token，驗證 ID Token（簽章、issuer、audience、時效與 nonce）並取得用戶資訊
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*entity.ExternalProfile, error) {
+	oauth, verifier, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = gooidc.ClientContext(ctx, p.client)
+	token, err := oauth.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("token response has no id_token")
+	}
+	idToken, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("id_token nonce mismatch")
+	}
+
+	var claims struct {
+		Email             string `json:"email"`
+		EmailVerified     bool   `json:"email_verified"`
+		Name              string `json:"name"`
+		PreferredUsername string `json:"preferred_username"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("failed to parse id_token claims: %w", err)
+	}
+
+	groups, err := p.groups(idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.ExternalProfile{
+		Provider:      idToken.Issuer,
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+		Username:      claims.PreferredUsername,
+		Groups:        groups,
+	}, nil
+}
+
+// groups 取得群組 claim，可為字串陣列或單一字串
+func (p *Provider) groups(idToken *gooidc.IDToken) ([]string, error) {
+	if p.cfg.GroupsClaim == "" {
+		return nil, nil
+	}
+
+	var all map[string]interface{}
+	if err := idToken.Claims(&all); err != nil {
+		return nil, fmt.Errorf("failed to parse id_token claims: %w", err)
+	}
+
+	switch v := all[p.cfg.GroupsClaim].(type) {
+	case []interface{}:
+		groups := make([]string, 0, len(v))
+		for _, g := range v {
+			if s, ok := g.(string); ok {
+				groups = append(groups, s)
+			}
+		}
+		return groups, nil
+	case string:
+		return []string{v}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// discover 取得 discovery document，失敗時下次呼叫重試
+func (p *Provider) discover(ctx context.Context) (*oauth2.Config, *gooidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.provider != nil {
+		return p.oauth, p.verifier, nil
+	}
+
+	provider, err := gooidc.NewProvider(gooidc.ClientContext(ctx, p.client), p.cfg.IssuerURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to discover oidc provider: %w", err)
+	}
+
+	// 驗證 ID Token 時以獨立的 context 取得 JWKS，避免快取的 key set 綁定於單一請求
+	verifierCtx := gooidc.ClientContext(context.Background(), p.client)
+	p.provider = provider
+	p.verifier = provider.VerifierContext(verifierCtx, &gooidc.Config{ClientID: p.cfg.ClientID})
+	p.oauth = &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		RedirectURL:  p.cfg.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       p.cfg.Scopes,
+	}
+	return p.oauth, p.verifier, nil
+}
@@ -0,0 +1,173 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"sync_drive_backend/internal/infrastructure/oidc/idptest"
+)
+
+// newTestProvider 創建連線至測試 IdP 的 Provider
+func newTestProvider(idp *idptest.Server) *Provider {
+	return NewProvider(&Config{
+		IssuerURL:    idp.URL,
+		ClientID:     idp.ClientID,
+		ClientSecret: idp.ClientSecret,
+		RedirectURL:  idp.RedirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+		GroupsClaim:  "groups",
+		Timeout:      5 * time.Second,
+	})
+}
+
+// authorize 產生授權網址並於 IdP 完成登入，返回授權碼
+func authorize(t *testing.T, idp *idptest.Server, p *Provider, nonce, verifier string, claims map[string]any) string {
+	t.Helper()
+
+	authURL, err := p.AuthCodeURL(context.Background(), "state", nonce, verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	return idp.Authorize(t, authURL, claims)
+}
+
+func TestProviderExchange(t *testing.T) {
+	idp := idptest.NewServer(t)
+	p := newTestProvider(idp)
+	verifier := rand.Text() + rand.Text()
+
+	code := authorize(t, idp, p, "nonce-1", verifier, map[string]any{
+		"sub":                "alice-sub",
+		"email":              "alice@example.com",
+		"email_verified":     true,
+		"name":               "Alice",
+		"preferred_username": "alice",
+		"groups":             []string{"drivers", "fleet-a"},
+	})
+
+	profile, err := p.Exchange(context.Background(), code, verifier, "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if profile.Provider != idp.URL || profile.Subject != "alice-sub" {
+		t.Errorf("identity = %q/%q, want %q/alice-sub", profile.Provider, profile.Subject, idp.URL)
+	}
+	if profile.Email != "alice@example.com" || !profile.EmailVerified {
+		t.Errorf("email = %q (verified %v)", profile.Email, profile.EmailVerified)
+	}
+	if profile.Name != "Alice" || profile.Username != "alice" {
+		t.Errorf("name = %q, username = %q", profile.Name, profile.Username)
+	}
+	if !slices.Equal(profile.Groups, []string{"drivers", "fleet-a"}) {
+		t.Errorf("groups = %v", profile.Groups)
+	}
+}
+
+func TestProviderExchangeSingleGroup(t *testing.T) {
+	idp := idptest.NewServer(t)
+	p := newTestProvider(idp)
+	verifier := rand.Text() + rand.Text()
+
+	code := authorize(t, idp, p, "nonce-1", verifier, map[string]any{"groups": "drivers"})
+
+	profile, err := p.Exchange(context.Background(), code, verifier, "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if !slices.Equal(profile.Groups, []string{"drivers"}) {
+		t.Errorf("groups = %v, want [drivers]", profile.Groups)
+	}
+}
+
+func TestProviderExchangeRejects(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		claims  map[string]any
+		setup   func(idp *idptest.Server)
+		nonce   string // 交給 Exchange 的 nonce，空值與授權時相同
+		badPKCE bool   // 以不同的 code_verifier 換取
+		wantErr string
+	}{
+		{
+			name:    "nonce mismatch",
+			nonce:   "nonce-2",
+			wantErr: "nonce mismatch",
+		},
+		{
+			name:    "bad signature",
+			setup:   func(idp *idptest.Server) { idp.SignWith(otherKey) },
+			wantErr: "invalid id_token",
+		},
+		{
+			name:    "wrong audience",
+			claims:  map[string]any{"aud": "another-client"},
+			wantErr: "invalid id_token",
+		},
+		{
+			name:    "wrong issuer",
+			claims:  map[string]any{"iss": "https://evil.example.com"},
+			wantErr: "invalid id_token",
+		},
+		{
+			name:    "expired",
+			claims:  map[string]any{"exp": time.Now().Add(-time.Minute).Unix()},
+			wantErr: "invalid id_token",
+		},
+		{
+			name:    "pkce verifier mismatch",
+			badPKCE: true,
+			wantErr: "failed to exchange authorization code",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := idptest.NewServer(t)
+			if tt.setup != nil {
+				tt.setup(idp)
+			}
+			p := newTestProvider(idp)
+			verifier := rand.Text() + rand.Text()
+
+			code := authorize(t, idp, p, "nonce-1", verifier, tt.claims)
+
+			nonce := "nonce-1"
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+			if tt.badPKCE {
+				verifier = rand.Text() + rand.Text()
+			}
+
+			_, err := p.Exchange(context.Background(), code, verifier, nonce)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Exchange error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProviderExchangeCodeSingleUse(t *testing.T) {
+	idp := idptest.NewServer(t)
+	p := newTestProvider(idp)
+	verifier := rand.Text() + rand.Text()
+
+	code := authorize(t, idp, p, "nonce-1", verifier, nil)
+
+	if _, err := p.Exchange(context.Background(), code, verifier, "nonce-1"); err != nil {
+		t.Fatalf("first Exchange: %v", err)
+	}
+	if _, err := p.Exchange(context.Background(), code, verifier, "nonce-1"); err == nil {
+		t.Fatal("second Exchange with the same code succeeded")
+	}
+}
@@ -0,0 +1,312 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"sync_drive_backend/pkg/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// changeStreamHistoryLost resume token 已超出 oplog 範圍
+const changeStreamHistoryLost = 286
+
+// ChangeEvent Change Stream 變更事件
+type ChangeEvent struct {
+	OperationType     string              `bson:"operationType"` // insert, update, replace, delete ...
+	DocumentKey       bson.Raw            `bson:"documentKey"`
+	FullDocument      bson.Raw            `bson:"fullDocument"`
+	UpdateDescription *UpdateDescription  `bson:"updateDescription"`
+	ClusterTime       primitive.Timestamp `bson:"clusterTime"`
+	Namespace         struct {
+		Database   string `bson:"db"`
+		Collection string `bson:"coll"`
+	} `bson:"ns"`
+}
+
+// UpdateDescription update 事件的欄位變更內容
+type UpdateDescription struct {
+	UpdatedFields bson.Raw `bson:"updatedFields"`
+	RemovedFields []string `bson:"removedFields"`
+}
+
+// ChangeHandler 變更事件處理函數，返回錯誤時依設定重試
+type ChangeHandler func(ctx context.Context, evt *ChangeEvent) error
+
+// Subscription Change Stream 訂閱
+type Subscription struct {
+	Name         string               // 訂閱名稱，作為 resume token 的 key，必須唯一
+	Collection   string               // 監聽的集合
+	Pipeline     mongo.Pipeline       // 過濾條件，例如：{{"$match", bson.D{{"operationType", "insert"}}}}
+	FullDocument options.FullDocument // update 事件是否帶完整文件（預設不帶）
+	Handler      ChangeHandler
+}
+
+// ChangeStreamConfig Change Stream 訂閱器配置
+type ChangeStreamConfig struct {
+	MinBackoff     time.Duration // 重新連線的初始退避時間
+	MaxBackoff     time.Duration // 重新連線的退避上限
+	HandlerRetries int           // 處理失敗的重試次數，超過後略過該事件
+	TokenSaveEvery int           // 每處理 N 筆事件儲存一次 resume token
+	StoreOpTimeout time.Duration // resume token 存取逾時
+	MaxAwaitTime   time.Duration // 等待新事件的最長時間
+}
+
+// ChangeStreamSubscriber Change Stream 訂閱器
+// 每個訂閱獨立運行，斷線時以指數退避自動重連並從已儲存的 resume token 續傳
+type ChangeStreamSubscriber struct {
+	db    *mongo.Database
+	store ResumeTokenStore
+	cfg   *ChangeStreamConfig
+
+	mu     sync.Mutex
+	subs   []*Subscription
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewChangeStreamSubscriber 創建 Change Stream 訂閱器
+func NewChangeStreamSubscriber(db *mongo.Database, store ResumeTokenStore, cfg *ChangeStreamConfig) *ChangeStreamSubscriber {
+	return &ChangeStreamSubscriber{
+		db:    db,
+		store: store,
+		cfg:   cfg,
+	}
+}
+
+// Register 註冊訂閱，需在 Start 之前呼叫
+func (s *ChangeStreamSubscriber) Register(sub Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return errors.New("change stream subscriber already started")
+	}
+	if sub.Name == "" || sub.Collection == "" || sub.Handler == nil {
+		return errors.New("subscription requires name, collection and handler")
+	}
+	for _, existing := range s.subs {
+		if existing.Name == sub.Name {
+			return fmt.Errorf("subscription %q already registered", sub.Name)
+		}
+	}
+
+	s.subs = append(s.subs, &sub)
+	return nil
+}
+
+// Start 啟動所有訂閱
+func (s *ChangeStreamSubscriber) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, sub := range s.subs {
+		s.wg.Add(1)
+		go func(sub *Subscription) {
+			defer s.wg.Done()
+			s.watch(ctx, sub)
+		}(sub)
+	}
+}
+
+// Stop 停止所有訂閱，等待處理中的事件完成並儲存 resume token
+func (s *ChangeStreamSubscriber) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.cancel = nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// watch 單一訂閱的重連迴圈
+func (s *ChangeStreamSubscriber) watch(ctx context.Context, sub *Subscription) {
+	backoff := s.cfg.MinBackoff
+
+	for ctx.Err() == nil {
+		consumed, err := s.consume(ctx, sub)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if consumed {
+			backoff = s.cfg.MinBackoff
+		}
+
+		var cmdErr mongo.CommandError
+		if errors.As(err, &cmdErr) && cmdErr.Code == changeStreamHistoryLost {
+			// resume token 已失效，只能從最新事件重新開始
+			logger.Warn("Change stream history lost, restarting from latest",
+				zap.String("subscription", sub.Name), zap.Error(err))
+			if err := s.saveToken(sub.Name, nil); err != nil {
+				logger.Error("Failed to reset resume token", zap.String("subscription", sub.Name), zap.Error(err))
+			}
+			continue
+		}
+
+		logger.Warn("Change stream disconnected, reconnecting",
+			zap.String("subscription", sub.Name),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > s.cfg.MaxBackoff {
+			backoff = s.cfg.MaxBackoff
+		}
+	}
+}
+
+// consume 開啟 Change Stream 並處理事件直到錯誤或停止，返回是否曾成功處理事件
+func (s *ChangeStreamSubscriber) consume(ctx context.Context, sub *Subscription) (bool, error) {
+	token, err := s.loadToken(sub.Name)
+	if err != nil {
+		return false, fmt.Errorf("failed to load resume token: %w", err)
+	}
+
+	opts := options.ChangeStream().SetMaxAwaitTime(s.cfg.MaxAwaitTime)
+	if sub.FullDocument != "" {
+		opts.SetFullDocument(sub.FullDocument)
+	}
+	if token != nil {
+		opts.SetStartAfter(token)
+	}
+
+	pipeline := sub.Pipeline
+	if pipeline == nil {
+		pipeline = mongo.Pipeline{}
+	}
+
+	stream, err := s.db.Collection(sub.Collection).Watch(ctx, pipeline, opts)
+	if err != nil {
+		return false, err
+	}
+	defer stream.Close(context.Background())
+
+	logger.Info("Change stream subscribed",
+		zap.String("subscription", sub.Name),
+		zap.String("collection", sub.Collection),
+		zap.Bool("resumed", token != nil),
+	)
+
+	consumed := false
+	pending := 0
+	lastToken := token
+
+	// 停止或斷線時儲存最後處理的 resume token
+	defer func() {
+		if pending > 0 {
+			if err := s.saveToken(sub.Name, lastToken); err != nil {
+				logger.Error("Failed to save resume token", zap.String("subscription", sub.Name), zap.Error(err))
+			}
+		}
+	}()
+
+	for stream.Next(ctx) {
+		var evt ChangeEvent
+		if err := stream.Decode(&evt); err != nil {
+			return consumed, fmt.Errorf("failed to decode change event: %w", err)
+		}
+
+		if err := s.handle(ctx, sub, &evt); err != nil {
+			// 停止中斷，不推進 resume token，重啟後重新處理
+			return consumed, err
+		}
+
+		consumed = true
+		lastToken = stream.ResumeToken()
+		pending++
+
+		if pending >= s.cfg.TokenSaveEvery {
+			if err := s.saveToken(sub.Name, lastToken); err != nil {
+				return consumed, fmt.Errorf("failed to save resume token: %w", err)
+			}
+			pending = 0
+		}
+	}
+
+	if err := stream.Err(); err != nil {
+		return consumed, err
+	}
+	return consumed, errors.New("change stream closed")
+}
+
+// handle 執行處理函數，失敗時重試，超過次數後略過事件
+func (s *ChangeStreamSubscriber) handle(ctx context.Context, sub *Subscription, evt *ChangeEvent) error {
+	var err error
+	for attempt := 0; attempt <= s.cfg.HandlerRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(s.cfg.MinBackoff * time.Duration(attempt)):
+			}
+		}
+
+		if err = sub.Handler(ctx, evt); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+
+	logger.Error("Change event handler failed, event skipped",
+		zap.String("subscription", sub.Name),
+		zap.String("operation", evt.OperationType),
+		zap.String("document_key", evt.DocumentKey.String()),
+		zap.Error(err),
+	)
+	return nil
+}
+
+// loadToken 讀取 resume token
+func (s *ChangeStreamSubscriber) loadToken(name string) (bson.Raw, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.StoreOpTimeout)
+	defer cancel()
+	return s.store.Load(ctx, name)
+}
+
+// saveToken 儲存 resume token，不受訂閱 context 取消影響
+func (s *ChangeStreamSubscriber) saveToken(name string, token bson.Raw) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.StoreOpTimeout)
+	defer cancel()
+	return s.store.Save(ctx, name, token)
+}
@@ -0,0 +1,102 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ResumeTokenStore Change Stream resume token 儲存介面
+type ResumeTokenStore interface {
+	// Load 讀取 resume token，不存在時返回 nil
+	Load(ctx context.Context, name string) (bson.Raw, error)
+	// Save 儲存 resume token，token 為 nil 時清除
+	Save(ctx context.Context, name string, token bson.Raw) error
+}
+
+// MongoTokenStore 將 resume token 存放於 MongoDB 集合
+type MongoTokenStore struct {
+	coll *mongo.Collection
+}
+
+// 確保實作介面
+var _ ResumeTokenStore = (*MongoTokenStore)(nil)
+
+// NewMongoTokenStore 創建 MongoDB resume token 儲存
+func NewMongoTokenStore(db *mongo.Database, collection string) *MongoTokenStore {
+	return &MongoTokenStore{coll: db.Collection(collection)}
+}
+
+// resumeTokenDoc resume token 文件
+type resumeTokenDoc struct {
+	Name      string    `bson:"_id"`
+	Token     bson.Raw  `bson:"token"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// Load 讀取 resume token
+func (s *MongoTokenStore) Load(ctx context.Context, name string) (bson.Raw, error) {
+	var doc resumeTokenDoc
+	err := s.coll.FindOne(ctx, bson.M{"_id": name}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return doc.Token, nil
+}
+
+// Save 儲存 resume token
+func (s *MongoTokenStore) Save(ctx context.Context, name string, token bson.Raw) error {
+	if token == nil {
+		_, err := s.coll.DeleteOne(ctx, bson.M{"_id": name})
+		return err
+	}
+
+	_, err := s.coll.ReplaceOne(ctx,
+		bson.M{"_id": name},
+		resumeTokenDoc{Name: name, Token: token, UpdatedAt: time.Now()},
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+// RedisTokenStore 將 resume token 存放於 Redis
+type RedisTokenStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// 確保實作介面
+var _ ResumeTokenStore = (*RedisTokenStore)(nil)
+
+// NewRedisTokenStore 創建 Redis resume token 儲存
+func NewRedisTokenStore(client redis.UniversalClient, prefix string) *RedisTokenStore {
+	return &RedisTokenStore{client: client, prefix: prefix}
+}
+
+// Load 讀取 resume token
+func (s *RedisTokenStore) Load(ctx context.Context, name string) (bson.Raw, error) {
+	b, err := s.client.Get(ctx, s.prefix+name).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bson.Raw(b), nil
+}
+
+// Save 儲存 resume token
+func (s *RedisTokenStore) Save(ctx context.Context, name string, token bson.Raw) error {
+	if token == nil {
+		return s.client.Del(ctx, s.prefix+name).Err()
+	}
+	return s.client.Set(ctx, s.prefix+name, []byte(token), 0).Err()
+}
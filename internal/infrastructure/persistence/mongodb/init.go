@@ -13,12 +13,17 @@ import (
 type Config struct {
 	URI      string
 	Database string
-	Timeout  int // 連接超時（秒）
+	Timeout  int // 連接與單次操作超時（秒）
+}
+
+// OperationTimeout 單次資料庫操作的逾時時間
+func (c *Config) OperationTimeout() time.Duration {
+	return time.Duration(c.Timeout) * time.Second
 }
 
 // Init 初始化 MongoDB 連接
 func Init(cfg *Config) (*mongo.Database, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Timeout)*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.OperationTimeout())
 	defer cancel()
 
 	// 創建客戶端選項
@@ -0,0 +1,46 @@
+package mongodb
+
+import (
+	"sync_drive_backend/internal/common/policy"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// PolicyFields 資源擁有者與所屬車隊對應的欄位，文件沒有該屬性時保持空字串
+type PolicyFields struct {
+	Owner string
+	Fleet string
+}
+
+// PolicyFilter 將授權範圍併入查詢條件，範圍為空時查無資料
+//
+//	scope, err := engine.Scope(ctx, subject, consts.PermissionVehicleRead)
+//	filter := mongodb.PolicyFilter(scope, mongodb.PolicyFields{Fleet: "fleet_id"}, bson.M{"status": "active"})
+func PolicyFilter(scope *policy.Scope, fields PolicyFields, filter bson.M) bson.M {
+	if scope.All {
+		return filter
+	}
+
+	var conds bson.A
+	if fields.Owner != "" && len(scope.OwnerIDs) > 0 {
+		conds = append(conds, bson.M{fields.Owner: bson.M{"$in": scope.OwnerIDs}})
+	}
+	if fields.Fleet != "" && len(scope.FleetIDs) > 0 {
+		conds = append(conds, bson.M{fields.Fleet: bson.M{"$in": scope.FleetIDs}})
+	}
+
+	var scoped bson.M
+	switch len(conds) {
+	case 0:
+		scoped = bson.M{"$expr": false}
+	case 1:
+		scoped = conds[0].(bson.M)
+	default:
+		scoped = bson.M{"$or": conds}
+	}
+
+	if len(filter) == 0 {
+		return scoped
+	}
+	return bson.M{"$and": bson.A{filter, scoped}}
+}
@@ -0,0 +1,22 @@
+package record
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Base MongoDB 文件共用欄位，以 `bson:",inline"` 嵌入
+type Base struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	CreatedAt time.Time          `bson:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+}
+
+// Touch 寫入前更新時間戳記，CreatedAt 僅在首次寫入時設定
+func (b *Base) Touch(now time.Time) {
+	if b.CreatedAt.IsZero() {
+		b.CreatedAt = now
+	}
+	b.UpdatedAt = now
+}
@@ -0,0 +1,11 @@
+package record
+
+import "sync_drive_backend/internal/infrastructure/persistence/mongodb"
+
+// Collections 由程式宣告的集合與索引，供 mongodb.SyncSchema 比對線上資料庫
+// 新增文件模型時，實作 CollectionSpec 並在此註冊
+func Collections() []mongodb.CollectionSpec {
+	return []mongodb.CollectionSpec{
+		SchedulerRunSpec(),
+	}
+}
@@ -0,0 +1,39 @@
+package record
+
+import (
+	"time"
+
+	"sync_drive_backend/internal/infrastructure/persistence/mongodb"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// SchedulerRunCollection 排程執行紀錄集合名稱
+const SchedulerRunCollection = "scheduler_runs"
+
+// schedulerRunRetention 排程執行紀錄保留時間
+const schedulerRunRetention = 30 * 24 * time.Hour
+
+// SchedulerRun 排程執行紀錄
+type SchedulerRun struct {
+	Base        `bson:",inline"`
+	Job         string    `bson:"job"`
+	Trigger     string    `bson:"trigger"`
+	Status      string    `bson:"status"`
+	Replica     string    `bson:"replica"`
+	ScheduledAt time.Time `bson:"scheduled_at"`
+	StartedAt   time.Time `bson:"started_at"`
+	FinishedAt  time.Time `bson:"finished_at,omitempty"`
+	Error       string    `bson:"error,omitempty"`
+}
+
+// SchedulerRunSpec 排程執行紀錄的集合宣告
+func SchedulerRunSpec() mongodb.CollectionSpec {
+	return mongodb.CollectionSpec{
+		Name: SchedulerRunCollection,
+		Indexes: []mongodb.IndexSpec{
+			{Name: "job_started_at", Keys: bson.D{{Key: "job", Value: 1}, {Key: "started_at", Value: -1}}},
+			{Name: "started_at_ttl", Keys: bson.D{{Key: "started_at", Value: 1}}, TTL: schedulerRunRetention},
+		},
+	}
+}
@@ -0,0 +1,370 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	apperrors "sync_drive_backend/pkg/errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// toucher 包含時間戳記的文件模型（嵌入 record.Base）
+type toucher interface {
+	Touch(now time.Time)
+}
+
+// FindOptions 查詢選項
+type FindOptions struct {
+	Sort       bson.D      // 排序，例如：bson.D{{Key: "created_at", Value: -1}}
+	Projection interface{} // 欄位投影，例如：bson.M{"payload": 0}
+	Skip       int64
+	Limit      int64
+}
+
+// BaseRepository MongoDB 泛型 Repository 基底
+// E 為 Domain Entity，R 為含 bson 標籤的文件模型，兩者透過 toRecord / toEntity 轉換
+// 每次操作都套用 mongodb.timeout 設定的逾時時間，並將驅動錯誤轉換為 AppError
+type BaseRepository[E any, R any] struct {
+	coll     *mongo.Collection
+	timeout  time.Duration
+	toRecord func(*E) *R
+	toEntity func(*R) *E
+}
+
+// NewBaseRepository 創建泛型 Repository 基底
+//
+//	type LogRepositoryImpl struct {
+//		*BaseRepository[entity.Log, record.Log]
+//	}
+//
+//	func NewLogRepository(db *mongo.Database, cfg *mongodb.Config) repository.ILogRepository {
+//		return &LogRepositoryImpl{
+//			BaseRepository: NewBaseRepository(db, "logs", cfg.OperationTimeout(), toLogRecord, toLogEntity),
+//		}
+//	}
+func NewBaseRepository[E any, R any](
+	db *mongo.Database,
+	collection string,
+	timeout time.Duration,
+	toRecord func(*E) *R,
+	toEntity func(*R) *E,
+) *BaseRepository[E, R] {
+	return &BaseRepository[E, R]{
+		coll:     db.Collection(collection),
+		timeout:  timeout,
+		toRecord: toRecord,
+		toEntity: toEntity,
+	}
+}
+
+// Collection 取得底層 Collection，供基底未涵蓋的查詢使用
+func (r *BaseRepository[E, R]) Collection() *mongo.Collection {
+	return r.coll
+}
+
+// Insert 新增文件，返回文件 _id
+func (r *BaseRepository[E, R]) Insert(ctx context.Context, e *E) (interface{}, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rec := r.toRecord(e)
+	touch(rec)
+
+	result, err := r.coll.InsertOne(ctx, rec)
+	if err != nil {
+		return nil, translateError(err, "failed to insert document")
+	}
+
+	return result.InsertedID, nil
+}
+
+// InsertMany 批次新增文件
+func (r *BaseRepository[E, R]) InsertMany(ctx context.Context, entities []*E) error {
+	if len(entities) == 0 {
+		return nil
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	docs := make([]interface{}, 0, len(entities))
+	for _, e := range entities {
+		rec := r.toRecord(e)
+		touch(rec)
+		docs = append(docs, rec)
+	}
+
+	if _, err := r.coll.InsertMany(ctx, docs); err != nil {
+		return translateError(err, "failed to insert documents")
+	}
+
+	return nil
+}
+
+// FindByID 依 _id 查詢文件，不存在時返回 ErrNotFound
+func (r *BaseRepository[E, R]) FindByID(ctx context.Context, id interface{}) (*E, error) {
+	return r.FindOne(ctx, bson.M{"_id": id}, nil)
+}
+
+// FindOne 查詢單筆文件，不存在時返回 ErrNotFound
+func (r *BaseRepository[E, R]) FindOne(ctx context.Context, filter interface{}, opts *FindOptions) (*E, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	findOpts := options.FindOne()
+	if opts != nil {
+		if opts.Sort != nil {
+			findOpts.SetSort(opts.Sort)
+		}
+		if opts.Projection != nil {
+			findOpts.SetProjection(opts.Projection)
+		}
+		if opts.Skip > 0 {
+			findOpts.SetSkip(opts.Skip)
+		}
+	}
+
+	var rec R
+	if err := r.coll.FindOne(ctx, filter, findOpts).Decode(&rec); err != nil {
+		return nil, translateError(err, "failed to find document")
+	}
+
+	return r.toEntity(&rec), nil
+}
+
+// Find 查詢多筆文件
+func (r *BaseRepository[E, R]) Find(ctx context.Context, filter interface{}, opts *FindOptions) ([]*E, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	cursor, err := r.coll.Find(ctx, filter, toFindOptions(opts))
+	if err != nil {
+		return nil, translateError(err, "failed to find documents")
+	}
+	defer cursor.Close(ctx)
+
+	var records []R
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, translateError(err, "failed to decode documents")
+	}
+
+	entities := make([]*E, 0, len(records))
+	for i := range records {
+		entities = append(entities, r.toEntity(&records[i]))
+	}
+
+	return entities, nil
+}
+
+// Iterate 以 cursor 逐筆處理文件，fn 返回錯誤時停止
+// 逾時設定僅套用於開啟 cursor，迭代期間以呼叫端的 context 控制，適合大量資料
+func (r *BaseRepository[E, R]) Iterate(ctx context.Context, filter interface{}, opts *FindOptions, fn func(*E) error) error {
+	openCtx, cancel := r.withTimeout(ctx)
+	cursor, err := r.coll.Find(openCtx, filter, toFindOptions(opts))
+	cancel()
+	if err != nil {
+		return translateError(err, "failed to find documents")
+	}
+	defer cursor.Close(context.Background())
+
+	for cursor.Next(ctx) {
+		var rec R
+		if err := cursor.Decode(&rec); err != nil {
+			return translateError(err, "failed to decode document")
+		}
+		if err := fn(r.toEntity(&rec)); err != nil {
+			return err
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return translateError(err, "failed to iterate documents")
+	}
+
+	return nil
+}
+
+// UpdateByID 以 $set 更新指定 _id 的文件，不存在時返回 ErrNotFound
+func (r *BaseRepository[E, R]) UpdateByID(ctx context.Context, id interface{}, set bson.M) error {
+	matched, err := r.Update(ctx, bson.M{"_id": id}, set)
+	if err != nil {
+		return err
+	}
+	if matched == 0 {
+		return apperrors.Wrap(apperrors.ErrNotFound, "document not found", mongo.ErrNoDocuments)
+	}
+	return nil
+}
+
+// Update 以 $set 更新符合條件的所有文件，返回符合筆數
+func (r *BaseRepository[E, R]) Update(ctx context.Context, filter interface{}, set bson.M) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	result, err := r.coll.UpdateMany(ctx, filter, bson.M{"$set": r.withUpdatedAt(set)})
+	if err != nil {
+		return 0, translateError(err, "failed to update documents")
+	}
+
+	return result.MatchedCount, nil
+}
+
+// Upsert 以文件模型的欄位更新符合條件的文件，不存在時新增
+// created_at 與 _id 僅在新增時寫入，既有文件保留原值；omitempty 且為零值的欄位不會被清除
+// 注意：文件模型的 _id 必須為空或與既有文件一致
+func (r *BaseRepository[E, R]) Upsert(ctx context.Context, filter interface{}, e *E) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rec := r.toRecord(e)
+	touch(rec)
+
+	raw, err := bson.Marshal(rec)
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalError, "failed to encode document", err)
+	}
+	set := bson.M{}
+	if err := bson.Unmarshal(raw, &set); err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalError, "failed to encode document", err)
+	}
+
+	setOnInsert := bson.M{}
+	for _, field := range []string{"_id", "created_at"} {
+		if v, ok := set[field]; ok {
+			setOnInsert[field] = v
+			delete(set, field)
+		}
+	}
+
+	update := bson.M{"$set": set}
+	if len(setOnInsert) > 0 {
+		update["$setOnInsert"] = setOnInsert
+	}
+
+	if _, err := r.coll.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+		return translateError(err, "failed to upsert document")
+	}
+
+	return nil
+}
+
+// DeleteByID 刪除指定 _id 的文件，不存在時返回 ErrNotFound
+func (r *BaseRepository[E, R]) DeleteByID(ctx context.Context, id interface{}) error {
+	deleted, err := r.Delete(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return apperrors.Wrap(apperrors.ErrNotFound, "document not found", mongo.ErrNoDocuments)
+	}
+	return nil
+}
+
+// Delete 刪除符合條件的所有文件，返回刪除筆數
+func (r *BaseRepository[E, R]) Delete(ctx context.Context, filter interface{}) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	result, err := r.coll.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, translateError(err, "failed to delete documents")
+	}
+
+	return result.DeletedCount, nil
+}
+
+// Count 計算符合條件的文件數量
+func (r *BaseRepository[E, R]) Count(ctx context.Context, filter interface{}) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	count, err := r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, translateError(err, "failed to count documents")
+	}
+
+	return count, nil
+}
+
+// withTimeout 套用單次操作逾時
+func (r *BaseRepository[E, R]) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, r.timeout)
+}
+
+// withUpdatedAt 文件模型包含時間戳記時自動更新 updated_at
+func (r *BaseRepository[E, R]) withUpdatedAt(set bson.M) bson.M {
+	if _, ok := any(new(R)).(toucher); !ok {
+		return set
+	}
+	if _, exists := set["updated_at"]; exists {
+		return set
+	}
+
+	merged := make(bson.M, len(set)+1)
+	for k, v := range set {
+		merged[k] = v
+	}
+	merged["updated_at"] = time.Now()
+	return merged
+}
+
+// touch 文件模型包含時間戳記時更新
+func touch(rec interface{}) {
+	if t, ok := rec.(toucher); ok {
+		t.Touch(time.Now())
+	}
+}
+
+// toFindOptions 轉換為驅動的查詢選項
+func toFindOptions(opts *FindOptions) *options.FindOptions {
+	findOpts := options.Find()
+	if opts == nil {
+		return findOpts
+	}
+	if opts.Sort != nil {
+		findOpts.SetSort(opts.Sort)
+	}
+	if opts.Projection != nil {
+		findOpts.SetProjection(opts.Projection)
+	}
+	if opts.Skip > 0 {
+		findOpts.SetSkip(opts.Skip)
+	}
+	if opts.Limit > 0 {
+		findOpts.SetLimit(opts.Limit)
+	}
+	return findOpts
+}
+
+// ObjectIDFromHex 將字串轉換為 ObjectID，格式錯誤時視為資源不存在
+func ObjectIDFromHex(id string) (primitive.ObjectID, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, apperrors.Wrap(apperrors.ErrNotFound, "invalid document id", err)
+	}
+	return oid, nil
+}
+
+// translateError 將驅動錯誤轉換為 AppError
+func translateError(err error, message string) error {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return apperrors.Wrap(apperrors.ErrNotFound, "document not found", err)
+	case mongo.IsDuplicateKeyError(err):
+		return apperrors.Wrap(apperrors.ErrAlreadyExists, "document already exists", err)
+	case errors.Is(err, context.DeadlineExceeded) || mongo.IsTimeout(err):
+		return apperrors.Wrap(apperrors.ErrDatabaseQueryFailed, "mongodb operation timed out", err)
+	case mongo.IsNetworkError(err):
+		return apperrors.Wrap(apperrors.ErrDatabaseConnectionFailed, message, err)
+	default:
+		return apperrors.Wrap(apperrors.ErrDatabaseQueryFailed, message, err)
+	}
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sync_drive_backend/internal/infrastructure/persistence/mongodb/record"
+	"sync_drive_backend/internal/infrastructure/scheduler"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SchedulerRunRepository 排程執行紀錄
+type SchedulerRunRepository struct {
+	*BaseRepository[scheduler.Run, record.SchedulerRun]
+}
+
+// 確保實作介面
+var _ scheduler.RunHistory = (*SchedulerRunRepository)(nil)
+
+// NewSchedulerRunRepository 創建排程執行紀錄 Repository
+func NewSchedulerRunRepository(db *mongo.Database, timeout time.Duration) *SchedulerRunRepository {
+	return &SchedulerRunRepository{
+		BaseRepository: NewBaseRepository(db, record.SchedulerRunCollection, timeout, toSchedulerRunRecord, toSchedulerRun),
+	}
+}
+
+// Start 寫入開始紀錄，以 run.ID 作為 _id，未設定時設定為產生的 ID
+func (r *SchedulerRunRepository) Start(ctx context.Context, run *scheduler.Run) error {
+	id, err := r.Insert(ctx, run)
+	if err != nil {
+		return err
+	}
+	if oid, ok := id.(primitive.ObjectID); ok && run.ID == "" {
+		run.ID = oid.Hex()
+	}
+	return nil
+}
+
+// Finish 更新結束時間、狀態與錯誤
+func (r *SchedulerRunRepository) Finish(ctx context.Context, run *scheduler.Run) error {
+	oid, err := ObjectIDFromHex(run.ID)
+	if err != nil {
+		return err
+	}
+
+	return r.UpdateByID(ctx, oid, bson.M{
+		"status":      string(run.Status),
+		"finished_at": run.FinishedAt,
+		"error":       run.Error,
+	})
+}
+
+// Recent 依開始時間倒序取得最近的紀錄
+func (r *SchedulerRunRepository) Recent(ctx context.Context, job string, limit int64) ([]*scheduler.Run, error) {
+	return r.Find(ctx, bson.M{"job": job}, &FindOptions{
+		Sort:  bson.D{{Key: "started_at", Value: -1}},
+		Limit: limit,
+	})
+}
+
+// toSchedulerRunRecord 轉換為文件模型
+func toSchedulerRunRecord(run *scheduler.Run) *record.SchedulerRun {
+	rec := &record.SchedulerRun{
+		Job:         run.Job,
+		Trigger:     string(run.Trigger),
+		Status:      string(run.Status),
+		Replica:     run.Replica,
+		ScheduledAt: run.ScheduledAt,
+		StartedAt:   run.StartedAt,
+		FinishedAt:  run.FinishedAt,
+		Error:       run.Error,
+	}
+	if oid, err := primitive.ObjectIDFromHex(run.ID); err == nil {
+		rec.ID = oid
+	}
+	return rec
+}
+
+// toSchedulerRun 轉換為執行紀錄
+func toSchedulerRun(rec *record.SchedulerRun) *scheduler.Run {
+	return &scheduler.Run{
+		ID:          rec.ID.Hex(),
+		Job:         rec.Job,
+		Trigger:     scheduler.Trigger(rec.Trigger),
+		Status:      scheduler.RunStatus(rec.Status),
+		Replica:     rec.Replica,
+		ScheduledAt: rec.ScheduledAt,
+		StartedAt:   rec.StartedAt,
+		FinishedAt:  rec.FinishedAt,
+		Error:       rec.Error,
+	}
+}
@@ -0,0 +1,352 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CollectionSpec 集合宣告：索引與建立集合時的選項
+// 由各文件模型（persistence/mongodb/record）宣告，透過 SyncSchema 與線上資料庫比對
+type CollectionSpec struct {
+	Name    string
+	Indexes []IndexSpec
+
+	// 以下選項僅在建立集合時生效，既有集合不一致時只會回報
+	TimeSeries  *options.TimeSeriesOptions // 時間序列集合
+	ExpireAfter time.Duration              // 時間序列集合的資料保留時間
+	Capped      *CappedSpec                // 固定大小集合
+
+	// Validator 以 collMod 同步，可於既有集合更新
+	Validator bson.D
+}
+
+// CappedSpec 固定大小集合選項
+type CappedSpec struct {
+	SizeBytes    int64
+	MaxDocuments int64
+}
+
+// IndexSpec 索引宣告，Name 為比對依據，必須唯一
+type IndexSpec struct {
+	Name          string
+	Keys          bson.D // 例如：bson.D{{Key: "vehicle_id", Value: 1}, {Key: "location", Value: "2dsphere"}}
+	Unique        bool
+	Sparse        bool
+	TTL           time.Duration // 大於 0 時建立 TTL 索引
+	PartialFilter bson.D        // 部分索引條件
+}
+
+// SyncOptions 同步選項
+type SyncOptions struct {
+	DropStale bool // 刪除未宣告的索引，並重建定義不一致的索引
+	DryRun    bool // 只比對與回報，不做任何變更
+}
+
+// SchemaReport 同步結果
+type SchemaReport struct {
+	CreatedCollections []string
+	CreatedIndexes     []string
+	ChangedIndexes     []string // 定義不一致（DropStale 時已重建）
+	StaleIndexes       []string // 未宣告（DropStale 時已刪除）
+	UpdatedValidators  []string
+	Warnings           []string
+}
+
+// HasDrift 是否存在差異
+func (r *SchemaReport) HasDrift() bool {
+	return len(r.CreatedCollections)+len(r.CreatedIndexes)+len(r.ChangedIndexes)+
+		len(r.StaleIndexes)+len(r.UpdatedValidators)+len(r.Warnings) > 0
+}
+
+// liveIndex 線上索引定義
+type liveIndex struct {
+	Name                    string        `bson:"name"`
+	Key                     bson.Raw      `bson:"key"`
+	Unique                  bool          `bson:"unique"`
+	Sparse                  bool          `bson:"sparse"`
+	ExpireAfterSeconds      bson.RawValue `bson:"expireAfterSeconds"`
+	PartialFilterExpression bson.Raw      `bson:"partialFilterExpression"`
+}
+
+// SyncSchema 比對宣告與線上資料庫，建立缺少的集合與索引，回報或刪除多餘的索引
+func SyncSchema(ctx context.Context, db *mongo.Database, specs []CollectionSpec, opts SyncOptions) (*SchemaReport, error) {
+	report := &SchemaReport{}
+
+	existing, err := db.ListCollectionSpecifications(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	collections := make(map[string]*mongo.CollectionSpecification, len(existing))
+	for _, c := range existing {
+		collections[c.Name] = c
+	}
+
+	for i := range specs {
+		spec := &specs[i]
+
+		if live, ok := collections[spec.Name]; ok {
+			if err := syncCollectionOptions(ctx, db, spec, live, opts, report); err != nil {
+				return report, err
+			}
+		} else {
+			report.CreatedCollections = append(report.CreatedCollections, spec.Name)
+			if !opts.DryRun {
+				if err := db.CreateCollection(ctx, spec.Name, createCollectionOptions(spec)); err != nil {
+					return report, fmt.Errorf("failed to create collection %s: %w", spec.Name, err)
+				}
+			}
+		}
+
+		if err := syncIndexes(ctx, db.Collection(spec.Name), spec, opts, report); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// syncCollectionOptions 比對既有集合的選項
+func syncCollectionOptions(
+	ctx context.Context,
+	db *mongo.Database,
+	spec *CollectionSpec,
+	live *mongo.CollectionSpecification,
+	opts SyncOptions,
+	report *SchemaReport,
+) error {
+	if spec.TimeSeries != nil && live.Type != "timeseries" {
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("%s: declared as time-series but existing collection is %s", spec.Name, live.Type))
+	}
+
+	if spec.Capped != nil {
+		if capped, ok := live.Options.Lookup("capped").BooleanOK(); !ok || !capped {
+			report.Warnings = append(report.Warnings,
+				fmt.Sprintf("%s: declared as capped but existing collection is not", spec.Name))
+		}
+	}
+
+	if spec.Validator == nil {
+		return nil
+	}
+
+	declared, err := extJSON(spec.Validator)
+	if err != nil {
+		return fmt.Errorf("failed to marshal validator of %s: %w", spec.Name, err)
+	}
+	var current string
+	if doc, ok := live.Options.Lookup("validator").DocumentOK(); ok {
+		current, _ = extJSON(doc)
+	}
+	if declared == current {
+		return nil
+	}
+
+	report.UpdatedValidators = append(report.UpdatedValidators, spec.Name)
+	if opts.DryRun {
+		return nil
+	}
+
+	cmd := bson.D{{Key: "collMod", Value: spec.Name}, {Key: "validator", Value: spec.Validator}}
+	if err := db.RunCommand(ctx, cmd).Err(); err != nil {
+		return fmt.Errorf("failed to update validator of %s: %w", spec.Name, err)
+	}
+
+	return nil
+}
+
+// syncIndexes 比對並同步單一集合的索引
+func syncIndexes(ctx context.Context, coll *mongo.Collection, spec *CollectionSpec, opts SyncOptions, report *SchemaReport) error {
+	cursor, err := coll.Indexes().List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list indexes of %s: %w", spec.Name, err)
+	}
+	var lives []liveIndex
+	if err := cursor.All(ctx, &lives); err != nil {
+		return fmt.Errorf("failed to decode indexes of %s: %w", spec.Name, err)
+	}
+
+	liveByName := make(map[string]*liveIndex, len(lives))
+	for i := range lives {
+		liveByName[lives[i].Name] = &lives[i]
+	}
+
+	var toCreate []mongo.IndexModel
+	declared := make(map[string]bool, len(spec.Indexes))
+
+	for i := range spec.Indexes {
+		idx := &spec.Indexes[i]
+		name := spec.Name + "." + idx.Name
+		declared[idx.Name] = true
+
+		live, ok := liveByName[idx.Name]
+		if !ok {
+			report.CreatedIndexes = append(report.CreatedIndexes, name)
+			toCreate = append(toCreate, indexModel(idx))
+			continue
+		}
+
+		same, err := sameIndex(idx, live)
+		if err != nil {
+			return fmt.Errorf("failed to compare index %s: %w", name, err)
+		}
+		if same {
+			continue
+		}
+
+		report.ChangedIndexes = append(report.ChangedIndexes, name)
+		if opts.DropStale && !opts.DryRun {
+			if _, err := coll.Indexes().DropOne(ctx, idx.Name); err != nil {
+				return fmt.Errorf("failed to drop index %s: %w", name, err)
+			}
+			toCreate = append(toCreate, indexModel(idx))
+		}
+	}
+
+	for _, live := range lives {
+		if live.Name == "_id_" || declared[live.Name] {
+			continue
+		}
+
+		report.StaleIndexes = append(report.StaleIndexes, spec.Name+"."+live.Name)
+		if opts.DropStale && !opts.DryRun {
+			if _, err := coll.Indexes().DropOne(ctx, live.Name); err != nil {
+				return fmt.Errorf("failed to drop index %s.%s: %w", spec.Name, live.Name, err)
+			}
+		}
+	}
+
+	if len(toCreate) == 0 || opts.DryRun {
+		return nil
+	}
+	if _, err := coll.Indexes().CreateMany(ctx, toCreate); err != nil {
+		return fmt.Errorf("failed to create indexes of %s: %w", spec.Name, err)
+	}
+
+	return nil
+}
+
+// createCollectionOptions 轉換為建立集合選項
+func createCollectionOptions(spec *CollectionSpec) *options.CreateCollectionOptions {
+	opts := options.CreateCollection()
+	if spec.TimeSeries != nil {
+		opts.SetTimeSeriesOptions(spec.TimeSeries)
+		if spec.ExpireAfter > 0 {
+			opts.SetExpireAfterSeconds(int64(spec.ExpireAfter.Seconds()))
+		}
+	}
+	if spec.Capped != nil {
+		opts.SetCapped(true).SetSizeInBytes(spec.Capped.SizeBytes)
+		if spec.Capped.MaxDocuments > 0 {
+			opts.SetMaxDocuments(spec.Capped.MaxDocuments)
+		}
+	}
+	if spec.Validator != nil {
+		opts.SetValidator(spec.Validator)
+	}
+	return opts
+}
+
+// indexModel 轉換為驅動的索引模型
+func indexModel(idx *IndexSpec) mongo.IndexModel {
+	opts := options.Index().SetName(idx.Name)
+	if idx.Unique {
+		opts.SetUnique(true)
+	}
+	if idx.Sparse {
+		opts.SetSparse(true)
+	}
+	if idx.TTL > 0 {
+		opts.SetExpireAfterSeconds(int32(idx.TTL.Seconds()))
+	}
+	if idx.PartialFilter != nil {
+		opts.SetPartialFilterExpression(idx.PartialFilter)
+	}
+	return mongo.IndexModel{Keys: idx.Keys, Options: opts}
+}
+
+// sameIndex 比對宣告與線上索引的定義
+func sameIndex(idx *IndexSpec, live *liveIndex) (bool, error) {
+	keys, err := bson.Marshal(idx.Keys)
+	if err != nil {
+		return false, err
+	}
+	if keySignature(keys) != keySignature(live.Key) {
+		return false, nil
+	}
+
+	if idx.Unique != live.Unique || idx.Sparse != live.Sparse {
+		return false, nil
+	}
+
+	var liveTTL int64 = -1
+	if seconds, ok := live.ExpireAfterSeconds.AsInt64OK(); ok {
+		liveTTL = seconds
+	}
+	declaredTTL := int64(-1)
+	if idx.TTL > 0 {
+		declaredTTL = int64(idx.TTL.Seconds())
+	}
+	if declaredTTL != liveTTL {
+		return false, nil
+	}
+
+	var declaredFilter, liveFilter string
+	if idx.PartialFilter != nil {
+		if declaredFilter, err = extJSON(idx.PartialFilter); err != nil {
+			return false, err
+		}
+	}
+	if live.PartialFilterExpression != nil {
+		if liveFilter, err = extJSON(live.PartialFilterExpression); err != nil {
+			return false, err
+		}
+	}
+
+	return declaredFilter == liveFilter, nil
+}
+
+// keySignature 將索引鍵正規化為字串，忽略數值型別差異（int32 / int64 / double）
+func keySignature(keys bson.Raw) string {
+	elems, err := keys.Elements()
+	if err != nil {
+		return ""
+	}
+
+	parts := make([]string, 0, len(elems))
+	for _, e := range elems {
+		v := e.Value()
+		var value string
+		switch v.Type {
+		case bsontype.String:
+			value = v.StringValue()
+		case bsontype.Double:
+			value = strconv.FormatFloat(v.Double(), 'f', -1, 64)
+		case bsontype.Int32, bsontype.Int64:
+			value = strconv.FormatInt(v.AsInt64(), 10)
+		default:
+			value = v.String()
+		}
+		parts = append(parts, e.Key()+":"+value)
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// extJSON 以 relaxed Extended JSON 表示文件，用於比對
+func extJSON(doc interface{}) (string, error) {
+	b, err := bson.MarshalExtJSON(doc, false, false)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes.TrimSpace(b)), nil
+}
@@ -0,0 +1,171 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"sync_drive_backend/internal/common/util"
+	"sync_drive_backend/pkg/errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// 對應 record mixin 的欄位名稱
+	fieldVersion   = "Version"
+	fieldCreatedBy = "CreatedBy"
+	fieldUpdatedBy = "UpdatedBy"
+
+	// settingLockedVersion 記錄本次更新所依據的版本號
+	settingLockedVersion = "optimistic_lock:version"
+)
+
+// ErrVersionConflict 樂觀鎖版本衝突（資料已被其他請求修改）
+var ErrVersionConflict = errors.New(errors.ErrVersionConflict, "record has been modified by another request")
+
+// registerCallbacks 註冊樂觀鎖與稽核欄位的 GORM callback
+func registerCallbacks(db *gorm.DB) error {
+	create := db.Callback().Create()
+	if err := create.Before("gorm:create").Register("audit:before_create", beforeCreate); err != nil {
+		return fmt.Errorf("failed to register create callback: %w", err)
+	}
+
+	update := db.Callback().Update()
+	if err := update.Before("gorm:update").Register("audit:before_update", beforeUpdate); err != nil {
+		return fmt.Errorf("failed to register update callback: %w", err)
+	}
+	if err := update.After("gorm:update").Register("optimistic_lock:after_update", afterUpdate); err != nil {
+		return fmt.Errorf("failed to register update callback: %w", err)
+	}
+
+	return nil
+}
+
+// beforeCreate 初始化版本號並填入建立者
+func beforeCreate(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	if field := db.Statement.Schema.LookUpField(fieldVersion); field != nil && isSingleRecord(db) {
+		if _, isZero := field.ValueOf(db.Statement.Context, db.Statement.ReflectValue); isZero {
+			db.Statement.SetColumn(field.DBName, int64(1), true)
+		}
+	}
+
+	if userID := operatorID(db.Statement.Context); userID != "" {
+		setIfPresent(db, fieldCreatedBy, userID)
+		setIfPresent(db, fieldUpdatedBy, userID)
+	}
+}
+
+// beforeUpdate 加上版本條件、遞增版本號並填入更新者
+func beforeUpdate(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	if userID := operatorID(db.Statement.Context); userID != "" {
+		setIfPresent(db, fieldUpdatedBy, userID)
+	}
+
+	field := db.Statement.Schema.LookUpField(fieldVersion)
+	if field == nil {
+		return
+	}
+
+	// 僅在能取得目前版本的單筆更新時啟用樂觀鎖
+	if !isSingleRecord(db) {
+		if _, ok := db.Statement.Dest.(map[string]interface{}); ok {
+			db.Statement.SetColumn(field.DBName, gorm.Expr(field.DBName+" + 1"), true)
+		}
+		return
+	}
+
+	value, isZero := field.ValueOf(db.Statement.Context, db.Statement.ReflectValue)
+	if isZero {
+		return
+	}
+	current := reflect.ValueOf(value).Int()
+
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: current},
+	}})
+	db.Statement.SetColumn(field.DBName, current+1, true)
+	db.Statement.Settings.Store(settingLockedVersion, current)
+}
+
+// afterUpdate 同步記憶體中的版本號，未命中任何資料列時視為版本衝突
+func afterUpdate(db *gorm.DB) {
+	value, ok := db.Statement.Settings.Load(settingLockedVersion)
+	if !ok || db.Error != nil || db.DryRun {
+		return
+	}
+
+	current := value.(int64)
+	field := db.Statement.Schema.LookUpField(fieldVersion)
+
+	if db.RowsAffected > 0 {
+		// 以 map 更新時 SetColumn 不會回寫資料模型，需手動同步
+		_ = field.Set(db.Statement.Context, db.Statement.ReflectValue, current+1)
+		return
+	}
+
+	_ = field.Set(db.Statement.Context, db.Statement.ReflectValue, current)
+	_ = db.AddError(ErrVersionConflict)
+}
+
+// isSingleRecord 判斷本次操作是否針對單一且可定址的資料模型
+func isSingleRecord(db *gorm.DB) bool {
+	rv := db.Statement.ReflectValue
+	return rv.IsValid() && rv.Kind() == reflect.Struct && rv.CanAddr()
+}
+
+// setIfPresent 資料模型包含指定欄位時才寫入
+func setIfPresent(db *gorm.DB, name string, value interface{}) {
+	field := db.Statement.Schema.LookUpField(name)
+	if field == nil {
+		return
+	}
+	if !isSingleRecord(db) {
+		if _, ok := db.Statement.Dest.(map[string]interface{}); !ok {
+			return
+		}
+	}
+	db.Statement.SetColumn(field.DBName, value, true)
+}
+
+// operatorID 取得目前操作者的用戶 ID
+func operatorID(ctx context.Context) string {
+	return util.GetUserIDFromContext(ctx)
+}
+
+// Restore 還原軟刪除的資料
+// 例如：mysql.Restore(ctx, db, &record.Order{}, "id = ?", id)
+func Restore(ctx context.Context, db *gorm.DB, model interface{}, query interface{}, args ...interface{}) error {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return fmt.Errorf("failed to parse model: %w", err)
+	}
+
+	field := stmt.Schema.LookUpField("DeletedAt")
+	if field == nil {
+		return fmt.Errorf("model %s does not support soft delete", stmt.Schema.Name)
+	}
+
+	// 透過一般更新流程執行，UpdatedBy 由 beforeUpdate 填入
+	result := db.WithContext(ctx).Unscoped().Model(model).
+		Where(query, args...).
+		Where(field.DBName + " IS NOT NULL").
+		Updates(map[string]interface{}{field.DBName: nil})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
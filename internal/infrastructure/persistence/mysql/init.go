@@ -37,12 +37,18 @@ func Init(cfg *Config) (*gorm.DB, error) {
 
 	// 連接 MySQL
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger:         logger.Default.LogMode(logger.Info),
+		TranslateError: true, // 將驅動錯誤轉換為 gorm.ErrDuplicatedKey 等通用錯誤
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect mysql: %w", err)
 	}
 
+	// 註冊樂觀鎖與稽核欄位 callback
+	if err := registerCallbacks(db); err != nil {
+		return nil, err
+	}
+
 	// 取得底層 *sql.DB 並設定連接池
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -0,0 +1,48 @@
+package mysql
+
+import (
+	"sync_drive_backend/internal/common/policy"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PolicyColumns 資源擁有者與所屬車隊對應的欄位，資料表沒有該屬性時保持空字串
+type PolicyColumns struct {
+	Owner string
+	Fleet string
+}
+
+// PolicyScope 將授權範圍轉換為查詢條件，範圍為空時查無資料
+//
+//	scope, err := engine.Scope(ctx, subject, consts.PermissionOrderRead)
+//	db.WithContext(ctx).Scopes(mysql.PolicyScope(scope, mysql.PolicyColumns{Owner: "driver_id", Fleet: "fleet_id"})).Find(&orders)
+func PolicyScope(scope *policy.Scope, columns PolicyColumns) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if scope.All {
+			return db
+		}
+
+		var exprs []clause.Expression
+		if columns.Owner != "" && len(scope.OwnerIDs) > 0 {
+			exprs = append(exprs, inColumn(columns.Owner, scope.OwnerIDs))
+		}
+		if columns.Fleet != "" && len(scope.FleetIDs) > 0 {
+			exprs = append(exprs, inColumn(columns.Fleet, scope.FleetIDs))
+		}
+
+		if len(exprs) == 0 {
+			return db.Where("1 = 0")
+		}
+		return db.Where(clause.Or(exprs...))
+	}
+}
+
+// inColumn 目前資料表欄位的 IN 條件
+func inColumn(column string, ids []string) clause.Expression {
+	values := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, id)
+	}
+	return clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: column}, Values: values}
+}
@@ -0,0 +1,34 @@
+package record
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 以下為可嵌入的 GORM 資料模型 mixin，對應的欄位行為由 mysql.Init 註冊的 callback 處理
+
+// Timestamps 建立與更新時間
+type Timestamps struct {
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// Versioned 樂觀鎖版本號
+// 更新時自動加上 WHERE version = ? 並遞增版本，未命中任何資料列時返回版本衝突錯誤
+type Versioned struct {
+	Version int64 `gorm:"not null;default:1"`
+}
+
+// Auditable 操作者稽核欄位
+// 新增時填入 CreatedBy 與 UpdatedBy，更新時填入 UpdatedBy，值取自 context 中已驗證的用戶
+type Auditable struct {
+	CreatedBy string `gorm:"size:64"`
+	UpdatedBy string `gorm:"size:64"`
+}
+
+// SoftDelete 軟刪除
+// 刪除時僅寫入 DeletedAt，查詢自動排除已刪除資料，可透過 mysql.Restore 還原
+type SoftDelete struct {
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
@@ -0,0 +1,30 @@
+package record
+
+import "time"
+
+// Outbox 訊息狀態
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+	OutboxStatusFailed  = "failed"
+)
+
+// OutboxMessage 交易式 Outbox 資料模型
+type OutboxMessage struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement"`
+	MessageID   string    `gorm:"size:36;uniqueIndex"`
+	Topic       string    `gorm:"size:255"`
+	EventType   string    `gorm:"size:128"`
+	Payload     string    `gorm:"type:json"`
+	Status      string    `gorm:"size:16;index:idx_outbox_status_available,priority:1"`
+	Attempts    int       `gorm:"not null;default:0"`
+	LastError   string    `gorm:"size:1024"`
+	AvailableAt time.Time `gorm:"index:idx_outbox_status_available,priority:2"`
+	SentAt      *time.Time
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName 指定資料表名稱
+func (OutboxMessage) TableName() string {
+	return "outbox_messages"
+}
@@ -0,0 +1,39 @@
+package record
+
+import "time"
+
+// Role 角色資料模型
+type Role struct {
+	Name        string `gorm:"primaryKey;size:32"`
+	Description string `gorm:"size:255"`
+	BuiltIn     bool   `gorm:"not null;default:false"`
+	Timestamps
+}
+
+// TableName 指定資料表名稱
+func (Role) TableName() string {
+	return "roles"
+}
+
+// Permission 權限資料模型
+type Permission struct {
+	Code        string    `gorm:"primaryKey;size:64"`
+	Description string    `gorm:"size:255"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName 指定資料表名稱
+func (Permission) TableName() string {
+	return "permissions"
+}
+
+// RolePermission 角色權限對應資料模型
+type RolePermission struct {
+	Role       string `gorm:"primaryKey;size:32"`
+	Permission string `gorm:"primaryKey;size:64;index"`
+}
+
+// TableName 指定資料表名稱
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
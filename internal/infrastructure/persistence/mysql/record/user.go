@@ -0,0 +1,31 @@
+package record
+
+import "time"
+
+// User 用戶資料模型
+type User struct {
+	ID                string `gorm:"primaryKey;size:36"`
+	Username          string `gorm:"size:64;uniqueIndex:uk_users_username"`
+	Email             string `gorm:"size:255;uniqueIndex:uk_users_email"`
+	PasswordHash      string `gorm:"size:255"`
+	DisplayName       string `gorm:"size:128"`
+	Phone             string `gorm:"size:32"`
+	Role              string `gorm:"size:32;index"`
+	Status            string `gorm:"size:16"`
+	EmailVerified     bool   `gorm:"not null;default:false"`
+	PasswordChangedAt time.Time
+	LastLoginAt       *time.Time
+	MFAEnabled        bool     `gorm:"column:mfa_enabled;not null;default:false"`
+	MFASecret         string   `gorm:"column:mfa_secret;size:255"`
+	MFALastCounter    int64    `gorm:"column:mfa_last_counter;not null;default:0"`
+	RecoveryCodes     []string `gorm:"column:mfa_recovery_codes;type:json;serializer:json"`
+	Timestamps
+	Versioned
+	Auditable
+	SoftDelete
+}
+
+// TableName 指定資料表名稱
+func (User) TableName() string {
+	return "users"
+}
@@ -0,0 +1,18 @@
+package record
+
+import "time"
+
+// UserIdentity 外部身份資料模型
+type UserIdentity struct {
+	ID        string    `gorm:"primaryKey;size:36"`
+	UserID    string    `gorm:"size:36;index"`
+	Provider  string    `gorm:"size:255;uniqueIndex:uk_user_identities_provider_subject"`
+	Subject   string    `gorm:"size:255;uniqueIndex:uk_user_identities_provider_subject"`
+	Email     string    `gorm:"size:255"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName 指定資料表名稱
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sync_drive_backend/internal/common/consts"
+	"sync_drive_backend/internal/core/auth/domain/entity"
+	"sync_drive_backend/internal/core/auth/domain/repository"
+	"sync_drive_backend/internal/infrastructure/persistence/mysql/record"
+	apperrors "sync_drive_backend/pkg/errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RoleRepository 角色與權限儲存庫 MySQL 實作
+type RoleRepository struct {
+	db *gorm.DB
+}
+
+// 確保實作介面
+var _ repository.IRoleRepository = (*RoleRepository)(nil)
+
+// NewRoleRepository 創建角色與權限儲存庫
+func NewRoleRepository(db *gorm.DB) *RoleRepository {
+	return &RoleRepository{db: db}
+}
+
+// ListRoles 列出全部角色
+func (r *RoleRepository) ListRoles(ctx context.Context) ([]*entity.Role, error) {
+	var recs []record.Role
+	if err := r.db.WithContext(ctx).Order("name").Find(&recs).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrDatabaseQueryFailed, "failed to query roles", err)
+	}
+
+	var links []record.RolePermission
+	if err := r.db.WithContext(ctx).Order("permission").Find(&links).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrDatabaseQueryFailed, "failed to query role permissions", err)
+	}
+	permissions := make(map[string][]consts.Permission, len(recs))
+	for _, link := range links {
+		permissions[link.Role] = append(permissions[link.Role], consts.Permission(link.Permission))
+	}
+
+	roles := make([]*entity.Role, 0, len(recs))
+	for i := range recs {
+		roles = append(roles, toRoleEntity(&recs[i], permissions[recs[i].Name]))
+	}
+	return roles, nil
+}
+
+// FindRole 依名稱查詢
+func (r *RoleRepository) FindRole(ctx context.Context, name consts.Role) (*entity.Role, error) {
+	var rec record.Role
+	if err := r.db.WithContext(ctx).Where("name = ?", name.String()).Take(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrRoleNotFound
+		}
+		return nil, apperrors.Wrap(apperrors.ErrDatabaseQueryFailed, "failed to query role", err)
+	}
+
+	var codes []string
+	err := r.db.WithContext(ctx).Model(&record.RolePermission{}).
+		Where("role = ?", rec.Name).
+		Order("permission").
+		Pluck("permission", &codes).Error
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrDatabaseQueryFailed, "failed to query role permissions", err)
+	}
+
+	permissions := make([]consts.Permission, 0, len(codes))
+	for _, code := range codes {
+		permissions = append(permissions, consts.Permission(code))
+	}
+	return toRoleEntity(&rec, permissions), nil
+}
+
+// CreateRole 建立角色與其權限
+func (r *RoleRepository) CreateRole(ctx context.Context, role *entity.Role) error {
+	rec := &record.Role{
+		Name:        role.Name.String(),
+		Description: role.Description,
+		BuiltIn:     role.BuiltIn,
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(rec).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return repository.ErrRoleAlreadyExists
+			}
+			return err
+		}
+		return replaceRolePermissions(tx, rec.Name, role.Permissions)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrRoleAlreadyExists) || errors.Is(err, repository.ErrPermissionNotFound) {
+			return err
+		}
+		return apperrors.Wrap(apperrors.ErrDatabaseWriteFailed, "failed to create role", err)
+	}
+
+	role.CreatedAt = rec.CreatedAt
+	role.UpdatedAt = rec.UpdatedAt
+	return nil
+}
+
+// UpdateRole 更新角色說明
+func (r *RoleRepository) UpdateRole(ctx context.Context, role *entity.Role) error {
+	result := r.db.WithContext(ctx).Model(&record.Role{Name: role.Name.String()}).
+		Update("description", role.Description)
+	if result.Error != nil {
+		return apperrors.Wrap(apperrors.ErrDatabaseWriteFailed, "failed to update role", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrRoleNotFound
+	}
+	return nil
+}
+
+// DeleteRole 刪除角色與其權限對應
+func (r *RoleRepository) DeleteRole(ctx context.Context, name consts.Role) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rec record.Role
+		if err := tx.Where("name = ?", name.String()).Take(&rec).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return repository.ErrRoleNotFound
+			}
+			return err
+		}
+
+		var users int64
+		if err := tx.Model(&record.User{}).Where("role = ?", rec.Name).Count(&users).Error; err != nil {
+			return err
+		}
+		if users > 0 {
+			return repository.ErrRoleInUse
+		}
+
+		if err := tx.Where("role = ?", rec.Name).Delete(&record.RolePermission{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&rec).Error
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrRoleNotFound) || errors.Is(err, repository.ErrRoleInUse) {
+			return err
+		}
+		return apperrors.Wrap(apperrors.ErrDatabaseWriteFailed, "failed to delete role", err)
+	}
+	return nil
+}
+
+// SetRolePermissions 取代角色的全部權限
+func (r *RoleRepository) SetRolePermissions(ctx context.Context, name consts.Role, permissions []consts.Permission) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rec record.Role
+		if err := tx.Where("name = ?", name.String()).Take(&rec).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return repository.ErrRoleNotFound
+			}
+			return err
+		}
+
+		if err := tx.Where("role = ?", rec.Name).Delete(&record.RolePermission{}).Error; err != nil {
+			return err
+		}
+		if err := replaceRolePermissions(tx, rec.Name, permissions); err != nil {
+			return err
+		}
+
+		// 更新時間作為權限最後異動時間
+		return tx.Model(&rec).Update("updated_at", time.Now()).Error
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrRoleNotFound) || errors.Is(err, repository.ErrPermissionNotFound) {
+			return err
+		}
+		return apperrors.Wrap(apperrors.ErrDatabaseWriteFailed, "failed to set role permissions", err)
+	}
+	return nil
+}
+
+// ListPermissions 列出全部權限
+func (r *RoleRepository) ListPermissions(ctx context.Context) ([]*entity.Permission, error) {
+	var recs []record.Permission
+	if err := r.db.WithContext(ctx).Order("code").Find(&recs).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrDatabaseQueryFailed, "failed to query permissions", err)
+	}
+
+	permissions := make([]*entity.Permission, 0, len(recs))
+	for i := range recs {
+		permissions = append(permissions, &entity.Permission{
+			Code:        consts.Permission(recs[i].Code),
+			Description: recs[i].Description,
+			CreatedAt:   recs[i].CreatedAt,
+		})
+	}
+	return permissions, nil
+}
+
+// CreatePermission 建立權限
+func (r *RoleRepository) CreatePermission(ctx context.Context, permission *entity.Permission) error {
+	rec := &record.Permission{
+		Code:        permission.Code.String(),
+		Description: permission.Description,
+	}
+	if err := r.db.WithContext(ctx).Create(rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return repository.ErrPermissionAlreadyExists
+		}
+		return apperrors.Wrap(apperrors.ErrDatabaseWriteFailed, "failed to create permission", err)
+	}
+
+	permission.CreatedAt = rec.CreatedAt
+	return nil
+}
+
+// DeletePermission 刪除權限並自所有角色移除
+func (r *RoleRepository) DeletePermission(ctx context.Context, code consts.Permission) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("code = ?", code.String()).Delete(&record.Permission{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrPermissionNotFound
+		}
+		return tx.Where("permission = ?", code.String()).Delete(&record.RolePermission{}).Error
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrPermissionNotFound) {
+			return err
+		}
+		return apperrors.Wrap(apperrors.ErrDatabaseWriteFailed, "failed to delete permission", err)
+	}
+	return nil
+}
+
+// replaceRolePermissions 寫入角色權限對應，權限需已存在
+func replaceRolePermissions(tx *gorm.DB, role string, permissions []consts.Permission) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	codes := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		codes = append(codes, p.String())
+	}
+
+	// 鎖定權限，避免寫入時權限同時被刪除
+	var found int64
+	if err := tx.Model(&record.Permission{}).Clauses(clause.Locking{Strength: "SHARE"}).
+		Where("code IN ?", codes).Count(&found).Error; err != nil {
+		return err
+	}
+	if int(found) != len(codes) {
+		return repository.ErrPermissionNotFound
+	}
+
+	links := make([]record.RolePermission, 0, len(codes))
+	for _, code := range codes {
+		links = append(links, record.RolePermission{Role: role, Permission: code})
+	}
+	return tx.Create(&links).Error
+}
+
+// toRoleEntity 轉換為領域實體
+func toRoleEntity(rec *record.Role, permissions []consts.Permission) *entity.Role {
+	return &entity.Role{
+		Name:        consts.Role(rec.Name),
+		Description: rec.Description,
+		BuiltIn:     rec.BuiltIn,
+		Permissions: permissions,
+		CreatedAt:   rec.CreatedAt,
+		UpdatedAt:   rec.UpdatedAt,
+	}
+}
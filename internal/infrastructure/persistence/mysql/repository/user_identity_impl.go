@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"sync_drive_backend/internal/core/auth/domain/entity"
+	"sync_drive_backend/internal/core/auth/domain/repository"
+	"sync_drive_backend/internal/infrastructure/persistence/mysql/record"
+	apperrors "sync_drive_backend/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// UserIdentityRepository 外部身份儲存庫 MySQL 實作
+type UserIdentityRepository struct {
+	db *gorm.DB
+}
+
+// 確保實作介面
+var _ repository.IUserIdentityRepository = (*UserIdentityRepository)(nil)
+
+// NewUserIdentityRepository 創建外部身份儲存庫
+func NewUserIdentityRepository(db *gorm.DB) *UserIdentityRepository {
+	return &UserIdentityRepository{db: db}
+}
+
+// FindByProviderSubject 依 Provider 與 Subject 查詢
+func (r *UserIdentityRepository) FindByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	var rec record.UserIdentity
+	err := r.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).Take(&rec).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrIdentityNotFound
+		}
+		return nil, apperrors.Wrap(apperrors.ErrDatabaseQueryFailed, "failed to query user identity", err)
+	}
+	return toUserIdentityEntity(&rec), nil
+}
+
+// Link 綁定至既有用戶
+func (r *UserIdentityRepository) Link(ctx context.Context, identity *entity.UserIdentity) error {
+	rec := toUserIdentityRecord(identity)
+	if err := r.db.WithContext(ctx).Create(rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return repository.ErrIdentityAlreadyLinked
+		}
+		return apperrors.Wrap(apperrors.ErrDatabaseWriteFailed, "failed to link user identity", err)
+	}
+
+	identity.CreatedAt = rec.CreatedAt
+	return nil
+}
+
+// Provision 建立用戶並綁定外部身份
+func (r *UserIdentityRepository) Provision(ctx context.Context, user *entity.User, identity *entity.UserIdentity) error {
+	userRec := toUserRecord(user)
+	identityRec := toUserIdentityRecord(identity)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(userRec).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return repository.ErrUserAlreadyExists
+			}
+			return err
+		}
+		if err := tx.Create(identityRec).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return repository.ErrIdentityAlreadyLinked
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrUserAlreadyExists) || errors.Is(err, repository.ErrIdentityAlreadyLinked) {
+			return err
+		}
+		return apperrors.Wrap(apperrors.ErrDatabaseWriteFailed, "failed to provision user", err)
+	}
+
+	user.Version = userRec.Version
+	user.CreatedAt = userRec.CreatedAt
+	user.UpdatedAt = userRec.UpdatedAt
+	identity.CreatedAt = identityRec.CreatedAt
+	return nil
+}
+
+// toUserIdentityRecord 轉換為資料模型
+func toUserIdentityRecord(identity *entity.UserIdentity) *record.UserIdentity {
+	return &record.UserIdentity{
+		ID:        identity.ID,
+		UserID:    identity.UserID,
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+	}
+}
+
+// toUserIdentityEntity 轉換為領域實體
+func toUserIdentityEntity(rec *record.UserIdentity) *entity.UserIdentity {
+	return &entity.UserIdentity{
+		ID:        rec.ID,
+		UserID:    rec.UserID,
+		Provider:  rec.Provider,
+		Subject:   rec.Subject,
+		Email:     rec.Email,
+		CreatedAt: rec.CreatedAt,
+	}
+}
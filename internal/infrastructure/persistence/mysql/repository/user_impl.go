@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"sync_drive_backend/internal/common/consts"
+	"sync_drive_backend/internal/core/auth/domain/entity"
+	"sync_drive_backend/internal/core/auth/domain/repository"
+	"sync_drive_backend/internal/infrastructure/persistence/mysql/record"
+	apperrors "sync_drive_backend/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// UserRepository 用戶儲存庫 MySQL 實作
+type UserRepository struct {
+	db *gorm.DB
+}
+
+// 確保實作介面
+var _ repository.IUserRepository = (*UserRepository)(nil)
+
+// NewUserRepository 創建用戶儲存庫
+func NewUserRepository(db *gorm.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// Create 建立用戶
+func (r *UserRepository) Create(ctx context.Context, user *entity.User) error {
+	rec := toUserRecord(user)
+	if err := r.db.WithContext(ctx).Create(rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return repository.ErrUserAlreadyExists
+		}
+		return apperrors.Wrap(apperrors.ErrDatabaseWriteFailed, "failed to create user", err)
+	}
+
+	user.Version = rec.Version
+	user.CreatedAt = rec.CreatedAt
+	user.UpdatedAt = rec.UpdatedAt
+	return nil
+}
+
+// FindByID 依 ID 查詢
+func (r *UserRepository) FindByID(ctx context.Context, id string) (*entity.User, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+// FindByUsername 依用戶名稱查詢
+func (r *UserRepository) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
+	return r.findOne(ctx, "username = ?", username)
+}
+
+// FindByEmail 依 Email 查詢
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	return r.findOne(ctx, "email = ?", email)
+}
+
+// Update 更新用戶
+func (r *UserRepository) Update(ctx context.Context, user *entity.User) error {
+	rec := toUserRecord(user)
+
+	err := r.db.WithContext(ctx).Model(rec).
+		Select("*").
+		Omit("created_at", "created_by", "deleted_at").
+		Updates(rec).Error
+	if err != nil {
+		if apperrors.IsCode(err, apperrors.ErrVersionConflict) {
+			return err
+		}
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return repository.ErrUserAlreadyExists
+		}
+		return apperrors.Wrap(apperrors.ErrDatabaseWriteFailed, "failed to update user", err)
+	}
+
+	user.Version = rec.Version
+	user.UpdatedAt = rec.UpdatedAt
+	return nil
+}
+
+// findOne 查詢單一用戶
+func (r *UserRepository) findOne(ctx context.Context, query string, args ...interface{}) (*entity.User, error) {
+	var rec record.User
+	err := r.db.WithContext(ctx).Where(query, args...).Take(&rec).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrUserNotFound
+		}
+		return nil, apperrors.Wrap(apperrors.ErrDatabaseQueryFailed, "failed to query user", err)
+	}
+	return toUserEntity(&rec), nil
+}
+
+// toUserRecord 轉換為資料模型
+func toUserRecord(user *entity.User) *record.User {
+	return &record.User{
+		ID:                user.ID,
+		Username:          user.Username,
+		Email:             user.Email,
+		PasswordHash:      user.PasswordHash,
+		DisplayName:       user.DisplayName,
+		Phone:             user.Phone,
+		Role:              user.Role.String(),
+		Status:            string(user.Status),
+		EmailVerified:     user.EmailVerified,
+		PasswordChangedAt: user.PasswordChangedAt,
+		LastLoginAt:       user.LastLoginAt,
+		MFAEnabled:        user.MFAEnabled,
+		MFASecret:         user.MFASecret,
+		MFALastCounter:    user.MFALastCounter,
+		RecoveryCodes:     user.RecoveryCodes,
+		Timestamps: record.Timestamps{
+			CreatedAt: user.CreatedAt,
+			UpdatedAt: user.UpdatedAt,
+		},
+		Versioned: record.Versioned{Version: user.Version},
+	}
+}
+
+// toUserEntity 轉換為領域實體
+func toUserEntity(rec *record.User) *entity.User {
+	return &entity.User{
+		ID:                rec.ID,
+		Username:          rec.Username,
+		Email:             rec.Email,
+		PasswordHash:      rec.PasswordHash,
+		DisplayName:       rec.DisplayName,
+		Phone:             rec.Phone,
+		Role:              consts.Role(rec.Role),
+		Status:            entity.UserStatus(rec.Status),
+		EmailVerified:     rec.EmailVerified,
+		PasswordChangedAt: rec.PasswordChangedAt,
+		LastLoginAt:       rec.LastLoginAt,
+		MFAEnabled:        rec.MFAEnabled,
+		MFASecret:         rec.MFASecret,
+		MFALastCounter:    rec.MFALastCounter,
+		RecoveryCodes:     rec.RecoveryCodes,
+		Version:           rec.Version,
+		CreatedAt:         rec.CreatedAt,
+		UpdatedAt:         rec.UpdatedAt,
+	}
+}
@@ -5,16 +5,35 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
 )
 
+// CacheConfig 快取配置
+type CacheConfig struct {
+	NegativeTTL time.Duration // 資料不存在時的負向快取時間
+	JitterRatio float64       // TTL 隨機延長比例，避免大量 key 同時過期（例如：0.1 為最多延長 10%）
+	Keys        *KeyBuilder   // 根 key 產生器，用於標籤集合的命名空間（nil 時不加前綴）
+}
+
 // Cache Redis 快取操作封裝
 type Cache struct {
-	client *redis.Client
+	client  redis.UniversalClient
+	cfg     *CacheConfig
+	group   singleflight.Group
+	cluster bool // Cluster 模式下標籤操作改為逐 key 執行
+}
+
+// NewCache 創建快取實例（使用預設配置）
+func NewCache(client redis.UniversalClient) *Cache {
+	return NewCacheWithConfig(client, &CacheConfig{
+		NegativeTTL: 30 * time.Second,
+		JitterRatio: 0.1,
+	})
 }
 
-// NewCache 創建快取實例
-func NewCache(client *redis.Client) *Cache {
-	return &Cache{client: client}
+// NewCacheWithConfig 使用完整配置創建快取實例
+func NewCacheWithConfig(client redis.UniversalClient, cfg *CacheConfig) *Cache {
+	return &Cache{client: client, cfg: cfg, cluster: IsCluster(client)}
 }
 
 // Set 設定快取
@@ -0,0 +1,125 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	apperrors "sync_drive_backend/pkg/errors"
+	"sync_drive_backend/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// negativeMarker 負向快取標記（資料不存在），不是合法的 JSON 因此不會與正常值混淆
+const negativeMarker = "\x00nil"
+
+// ErrCacheMiss 快取不存在（與 Redis 連線錯誤區分）
+var ErrCacheMiss = errors.New("cache miss")
+
+// Loader 快取未命中時載入資料
+// 返回錯誤碼為 errors.ErrNotFound 的 AppError 時會寫入負向快取
+type Loader[T any] func(ctx context.Context) (T, error)
+
+// GetJSON 取得 JSON 快取並反序列化
+// key 不存在時返回 ErrCacheMiss；命中負向快取時返回 ErrNotFound 的 AppError；其他為 Redis 錯誤
+func GetJSON[T any](ctx context.Context, c *Cache, key string) (T, error) {
+	var value T
+
+	b, err := c.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return value, ErrCacheMiss
+	}
+	if err != nil {
+		return value, err
+	}
+
+	if string(b) == negativeMarker {
+		return value, apperrors.New(apperrors.ErrNotFound, "resource not found")
+	}
+
+	if err := json.Unmarshal(b, &value); err != nil {
+		return value, fmt.Errorf("failed to unmarshal cache %s: %w", key, err)
+	}
+
+	return value, nil
+}
+
+// SetJSON 序列化為 JSON 並寫入快取，過期時間會加上隨機抖動
+func SetJSON[T any](ctx context.Context, c *Cache, key string, value T, ttl time.Duration) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache %s: %w", key, err)
+	}
+
+	return c.client.Set(ctx, key, b, c.jitter(ttl)).Err()
+}
+
+// GetOrLoad Cache-Aside 讀取
+// 未命中時呼叫 loader 載入並寫回快取，同一個 key 的並發未命中只會呼叫一次 loader（singleflight）
+// Redis 無法使用時直接呼叫 loader，不影響讀取
+func GetOrLoad[T any](ctx context.Context, c *Cache, key string, ttl time.Duration, loader Loader[T]) (T, error) {
+	return GetOrLoadWithTags(ctx, c, key, ttl, nil, loader)
+}
+
+// GetOrLoadWithTags 同 GetOrLoad，寫回快取（含負向快取）時關聯標籤
+func GetOrLoadWithTags[T any](ctx context.Context, c *Cache, key string, ttl time.Duration, tags []string, loader Loader[T]) (T, error) {
+	value, err := GetJSON[T](ctx, c, key)
+	switch {
+	case err == nil:
+		return value, nil
+	case apperrors.IsCode(err, apperrors.ErrNotFound):
+		return value, err
+	case !errors.Is(err, ErrCacheMiss):
+		logger.Warn("Cache unavailable, loading from source", zap.String("key", key), zap.Error(err))
+		return loader(ctx)
+	}
+
+	// loader 不受單一呼叫端取消影響，避免其他等待者一併失敗
+	ch := c.group.DoChan(key, func() (interface{}, error) {
+		loadCtx := context.WithoutCancel(ctx)
+
+		loaded, err := loader(loadCtx)
+		if apperrors.IsCode(err, apperrors.ErrNotFound) {
+			if setErr := c.SetWithTags(loadCtx, key, negativeMarker, c.cfg.NegativeTTL, tags...); setErr != nil {
+				logger.Warn("Failed to write negative cache", zap.String("key", key), zap.Error(setErr))
+			}
+			return loaded, err
+		}
+		if err != nil {
+			return loaded, err
+		}
+
+		if setErr := SetJSONWithTags(loadCtx, c, key, loaded, ttl, tags...); setErr != nil {
+			logger.Warn("Failed to write cache", zap.String("key", key), zap.Error(setErr))
+		}
+		return loaded, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	case result := <-ch:
+		loaded, _ := result.Val.(T)
+		return loaded, result.Err
+	}
+}
+
+// jitter 為過期時間加上隨機延長
+func (c *Cache) jitter(ttl time.Duration) time.Duration {
+	if ttl <= 0 || c.cfg.JitterRatio <= 0 {
+		return ttl
+	}
+
+	maxJitter := int64(float64(ttl) * c.cfg.JitterRatio)
+	if maxJitter <= 0 {
+		return ttl
+	}
+
+	return ttl + time.Duration(rand.Int64N(maxJitter))
+}
@@ -0,0 +1,158 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// setWithTagsScript 寫入快取並加入標籤集合
+// 標籤集合的過期時間不短於其成員，值不過期時標籤集合也不過期
+// KEYS[1]: 快取 key，KEYS[2..]: 標籤集合 key
+// ARGV[1]: 值，ARGV[2]: 過期時間（毫秒，0 表示不過期）
+var setWithTagsScript = redis.NewScript(`
+local ttl = tonumber(ARGV[2])
+if ttl > 0 then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ttl)
+else
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+for i = 2, #KEYS do
+	local existed = redis.call('EXISTS', KEYS[i])
+	local pttl = redis.call('PTTL', KEYS[i])
+	redis.call('SADD', KEYS[i], KEYS[1])
+	if ttl == 0 then
+		redis.call('PERSIST', KEYS[i])
+	elseif existed == 0 or (pttl >= 0 and pttl < ttl) then
+		redis.call('PEXPIRE', KEYS[i], ttl)
+	end
+end
+return 1
+`)
+
+// invalidateTagsScript 刪除標籤集合中的所有 key 與標籤集合本身，返回刪除的快取數量
+// KEYS: 標籤集合 key
+var invalidateTagsScript = redis.NewScript(`
+local deleted = 0
+for i = 1, #KEYS do
+	local members = redis.call('SMEMBERS', KEYS[i])
+	for j = 1, #members, 500 do
+		deleted = deleted + redis.call('DEL', unpack(members, j, math.min(j + 499, #members)))
+	end
+	redis.call('DEL', KEYS[i])
+end
+return deleted
+`)
+
+// addTagScript 將快取 key 加入單一標籤集合，過期時間規則同 setWithTagsScript
+// Cluster 模式下快取與標籤集合通常位於不同 slot，因此逐一標籤執行
+// KEYS[1]: 標籤集合 key，ARGV[1]: 快取 key，ARGV[2]: 過期時間（毫秒，0 表示不過期）
+var addTagScript = redis.NewScript(`
+local ttl = tonumber(ARGV[2])
+local existed = redis.call('EXISTS', KEYS[1])
+local pttl = redis.call('PTTL', KEYS[1])
+redis.call('SADD', KEYS[1], ARGV[1])
+if ttl == 0 then
+	redis.call('PERSIST', KEYS[1])
+elseif existed == 0 or (pttl >= 0 and pttl < ttl) then
+	redis.call('PEXPIRE', KEYS[1], ttl)
+end
+return 1
+`)
+
+// SetWithTags 設定快取並關聯標籤，之後可透過 InvalidateTags 一次刪除
+//
+//	cache.SetWithTags(ctx, key, value, time.Hour, "vehicle:42", "fleet:7")
+func (c *Cache) SetWithTags(ctx context.Context, key string, value interface{}, expiration time.Duration, tags ...string) error {
+	if c.cluster {
+		return c.setWithTagsCluster(ctx, key, value, expiration, tags)
+	}
+
+	keys := make([]string, 0, len(tags)+1)
+	keys = append(keys, key)
+	for _, tag := range tags {
+		keys = append(keys, c.tagKey(tag))
+	}
+
+	return setWithTagsScript.Run(ctx, c.client, keys, value, expiration.Milliseconds()).Err()
+}
+
+// InvalidateTags 原子地刪除與標籤關聯的所有快取，返回刪除數量
+func (c *Cache) InvalidateTags(ctx context.Context, tags ...string) (int64, error) {
+	if len(tags) == 0 {
+		return 0, nil
+	}
+
+	if c.cluster {
+		return c.invalidateTagsCluster(ctx, tags)
+	}
+
+	keys := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		keys = append(keys, c.tagKey(tag))
+	}
+
+	return invalidateTagsScript.Run(ctx, c.client, keys).Int64()
+}
+
+// setWithTagsCluster Cluster 模式的 SetWithTags，先加入標籤再寫入快取
+// 非原子操作：中途失敗時標籤集合可能多出不存在的 key，失效時會被一併忽略
+func (c *Cache) setWithTagsCluster(ctx context.Context, key string, value interface{}, expiration time.Duration, tags []string) error {
+	for _, tag := range tags {
+		if err := addTagScript.Run(ctx, c.client, []string{c.tagKey(tag)}, key, expiration.Milliseconds()).Err(); err != nil {
+			return err
+		}
+	}
+
+	return c.client.Set(ctx, key, value, expiration).Err()
+}
+
+// invalidateTagsCluster Cluster 模式的 InvalidateTags，逐 key 刪除（由客戶端依 slot 分派）
+func (c *Cache) invalidateTagsCluster(ctx context.Context, tags []string) (int64, error) {
+	var deleted int64
+
+	for _, tag := range tags {
+		tagKey := c.tagKey(tag)
+		members, err := c.client.SMembers(ctx, tagKey).Result()
+		if err != nil {
+			return deleted, err
+		}
+
+		pipe := c.client.Pipeline()
+		cmds := make([]*redis.IntCmd, 0, len(members))
+		for _, member := range members {
+			cmds = append(cmds, pipe.Del(ctx, member))
+		}
+		pipe.Del(ctx, tagKey)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return deleted, err
+		}
+
+		for _, cmd := range cmds {
+			deleted += cmd.Val()
+		}
+	}
+
+	return deleted, nil
+}
+
+// SetJSONWithTags 序列化為 JSON 並寫入快取，同時關聯標籤
+func SetJSONWithTags[T any](ctx context.Context, c *Cache, key string, value T, ttl time.Duration, tags ...string) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache %s: %w", key, err)
+	}
+
+	return c.SetWithTags(ctx, key, b, c.jitter(ttl), tags...)
+}
+
+// tagKey 標籤集合的 key
+func (c *Cache) tagKey(tag string) string {
+	if c.cfg.Keys == nil {
+		return "tag" + keySeparator + tag
+	}
+	return c.cfg.Keys.Key("tag", tag)
+}
@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"sync_drive_backend/internal/common/idempotency"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// beginIdempotencyScript 佔用冪等鍵，已存在時返回既有紀錄
+// KEYS[1]: 冪等紀錄，ARGV[1]: 處理中紀錄 JSON，ARGV[2]: 處理中存活時間（毫秒）
+// 返回 nil 表示佔用成功
+var beginIdempotencyScript = redis.NewScript(`
+local existing = redis.call('GET', KEYS[1])
+if existing then
+	return existing
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return false
+`)
+
+// IdempotencyStore 以 Redis 保存 Idempotency-Key 的回應
+type IdempotencyStore struct {
+	client redis.UniversalClient
+	keys   *KeyBuilder
+}
+
+// 確保實作介面
+var _ idempotency.Store = (*IdempotencyStore)(nil)
+
+// NewIdempotencyStore 創建冪等紀錄儲存
+func NewIdempotencyStore(client redis.UniversalClient, keys *KeyBuilder) *IdempotencyStore {
+	return &IdempotencyStore{
+		client: client,
+		keys:   keys,
+	}
+}
+
+// Begin 佔用 key 並標記為處理中
+func (s *IdempotencyStore) Begin(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (*idempotency.Record, bool, error) {
+	pending, err := json.Marshal(&idempotency.Record{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, false, err
+	}
+
+	raw, err := beginIdempotencyScript.Run(ctx, s.client, []string{s.key(key)}, pending, lockTTL.Milliseconds()).Text()
+	if err == redis.Nil {
+		return nil, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var existing idempotency.Record
+	if err := json.Unmarshal([]byte(raw), &existing); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal idempotency record: %w", err)
+	}
+	return &existing, false, nil
+}
+
+// Complete 保存回應
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, record *idempotency.Record, ttl time.Duration) error {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, s.key(key), b, ttl).Err()
+}
+
+// Release 釋放 key
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, s.key(key)).Err()
+}
+
+// key 冪等紀錄的 key
+func (s *IdempotencyStore) key(key string) string {
+	return s.keys.Key("idempotency", key)
+}
@@ -2,34 +2,163 @@ package redis
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// 部署模式
+const (
+	ModeStandalone = "standalone" // 單機
+	ModeSentinel   = "sentinel"   // Sentinel 高可用（主從自動切換）
+	ModeCluster    = "cluster"    // Cluster 分片
+)
+
 // Config Redis 配置
 type Config struct {
-	Host     string
-	Port     int
+	Mode string // standalone、sentinel、cluster，空值視為 standalone
+
+	// standalone 使用 Host / Port；sentinel 為 Sentinel 節點，cluster 為種子節點
+	Host  string
+	Port  int
+	Addrs []string
+
+	// Sentinel
+	MasterName       string
+	SentinelUsername string
+	SentinelPassword string
+
+	// 認證（Redis 6 ACL 使用 Username，舊版只需 Password）
+	Username string
 	Password string
-	DB       int
+
+	DB       int // cluster 模式只支援 0
 	PoolSize int
+
+	// TLS
+	TLS           bool
+	TLSCAFile     string // 自簽憑證的 CA，空值使用系統 CA
+	TLSServerName string
+	TLSSkipVerify bool
+
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
 }
 
-// Init 初始化 Redis 連接
-func Init(cfg *Config) (*redis.Client, error) {
-	client := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
-		Password: cfg.Password,
-		DB:       cfg.DB,
-		PoolSize: cfg.PoolSize,
-	})
+// Init 初始化 Redis 連接，依 Mode 建立單機、Sentinel 或 Cluster 客戶端
+// 返回 redis.UniversalClient，上層使用方式與部署模式無關
+func Init(cfg *Config) (redis.UniversalClient, error) {
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var client redis.UniversalClient
+	switch cfg.Mode {
+	case "", ModeStandalone:
+		client = redis.NewClient(&redis.Options{
+			Addr:         fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			DB:           cfg.DB,
+			PoolSize:     cfg.PoolSize,
+			TLSConfig:    tlsConfig,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+		})
+	case ModeSentinel:
+		if cfg.MasterName == "" || len(cfg.Addrs) == 0 {
+			return nil, fmt.Errorf("redis sentinel mode requires masterName and addrs")
+		}
+		client = redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       cfg.MasterName,
+			SentinelAddrs:    cfg.Addrs,
+			SentinelUsername: cfg.SentinelUsername,
+			SentinelPassword: cfg.SentinelPassword,
+			Username:         cfg.Username,
+			Password:         cfg.Password,
+			DB:               cfg.DB,
+			PoolSize:         cfg.PoolSize,
+			TLSConfig:        tlsConfig,
+			DialTimeout:      cfg.DialTimeout,
+			ReadTimeout:      cfg.ReadTimeout,
+			WriteTimeout:     cfg.WriteTimeout,
+		})
+	case ModeCluster:
+		if len(cfg.Addrs) == 0 {
+			return nil, fmt.Errorf("redis cluster mode requires addrs")
+		}
+		if cfg.DB != 0 {
+			return nil, fmt.Errorf("redis cluster mode only supports db 0")
+		}
+		client = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        cfg.Addrs,
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			PoolSize:     cfg.PoolSize,
+			TLSConfig:    tlsConfig,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported redis mode: %q", cfg.Mode)
+	}
 
 	// Ping 測試連接
 	ctx := context.Background()
 	if err := client.Ping(ctx).Err(); err != nil {
-		return nil, fmt.Errorf("failed to connect redis: %w", err)
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to connect redis (%s): %w", modeName(cfg.Mode), err)
 	}
 
 	return client, nil
 }
+
+// IsCluster 是否為 Cluster 客戶端
+// Cluster 模式下 Lua 腳本與 MULTI 的所有 key 必須位於同一個 slot
+func IsCluster(client redis.UniversalClient) bool {
+	_, ok := client.(*redis.ClusterClient)
+	return ok
+}
+
+// newTLSConfig 建立 TLS 配置，未啟用時返回 nil
+func newTLSConfig(cfg *Config) (*tls.Config, error) {
+	if !cfg.TLS {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.TLSServerName,
+		InsecureSkipVerify: cfg.TLSSkipVerify,
+	}
+
+	if cfg.TLSCAFile != "" {
+		pem, err := os.ReadFile(cfg.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read redis CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("failed to parse redis CA file: %s", cfg.TLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// modeName 部署模式名稱
+func modeName(mode string) string {
+	if mode == "" {
+		return ModeStandalone
+	}
+	return mode
+}
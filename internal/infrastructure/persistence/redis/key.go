@@ -0,0 +1,43 @@
+package redis
+
+import (
+	"strconv"
+	"strings"
+)
+
+// keySeparator key 各段的分隔符號
+const keySeparator = ":"
+
+// KeyBuilder 快取 key 產生器
+// 格式：{app}:{env}:{bc}:v{version}:{parts...}，不同 BC 與環境的 key 不會互相衝突
+// 資料結構變更時遞增 version，舊版本的 key 自然失效
+//
+//	keys := redis.NewKeyBuilder("sync-drive-backend", "production")
+//	fleetKeys := keys.WithBC("fleet", 1)
+//	fleetKeys.Key("vehicle", "42") // sync-drive-backend:production:fleet:v1:vehicle:42
+type KeyBuilder struct {
+	prefix string
+}
+
+// NewKeyBuilder 創建根 key 產生器
+func NewKeyBuilder(app, env string) *KeyBuilder {
+	return &KeyBuilder{prefix: app + keySeparator + env}
+}
+
+// WithBC 取得指定 Bounded Context 與版本的 key 產生器
+func (b *KeyBuilder) WithBC(bc string, version int) *KeyBuilder {
+	return &KeyBuilder{prefix: b.Key(bc, "v"+strconv.Itoa(version))}
+}
+
+// Key 組合完整的 key
+func (b *KeyBuilder) Key(parts ...string) string {
+	if len(parts) == 0 {
+		return b.prefix
+	}
+	return b.prefix + keySeparator + strings.Join(parts, keySeparator)
+}
+
+// Prefix 取得前綴
+func (b *KeyBuilder) Prefix() string {
+	return b.prefix
+}
@@ -0,0 +1,241 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"sync_drive_backend/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrLockNotAcquired 鎖已被其他持有者取得
+	ErrLockNotAcquired = errors.New("lock not acquired")
+	// ErrLockNotHeld 鎖已過期或已被其他持有者取得
+	ErrLockNotHeld = errors.New("lock not held")
+)
+
+// acquireScript 取得鎖並遞增 fencing token
+// KEYS[1]: 鎖 key，KEYS[2]: fencing token 計數器（不過期，確保單調遞增）
+// ARGV[1]: 持有者，ARGV[2]: 過期時間（毫秒）
+// 返回 fencing token，未取得時返回 0
+var acquireScript = redis.NewScript(`
+if redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+	return redis.call('INCR', KEYS[2])
+end
+return 0
+`)
+
+// releaseScript 持有者相符時才刪除鎖
+// KEYS[1]: 鎖 key，ARGV[1]: 持有者
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// extendScript 持有者相符時才延長過期時間
+// KEYS[1]: 鎖 key，ARGV[1]: 持有者，ARGV[2]: 過期時間（毫秒）
+var extendScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// LockerConfig 分散式鎖配置
+type LockerConfig struct {
+	RetryInterval time.Duration // Lock 等待時的重試間隔
+	Keys          *KeyBuilder   // key 前綴，nil 時不加前綴
+}
+
+// Locker Redis 分散式鎖
+// 以 SET NX PX 搭配隨機持有者取得鎖，Lua 腳本確保只有持有者能釋放或延長
+// 每次取得鎖都會返回單調遞增的 fencing token，寫入資料時一併保存並拒絕較舊的 token，
+// 可避免因 GC 停頓或網路延遲而過期的持有者覆寫資料
+//
+//	lock, err := locker.TryLock(ctx, "job:daily-report", 30*time.Second)
+//	if errors.Is(err, redis.ErrLockNotAcquired) {
+//		return nil // 其他副本正在執行
+//	}
+//	defer lock.Unlock(context.Background())
+//	// UPDATE reports SET ..., fencing_token = ? WHERE id = ? AND fencing_token < ?
+type Locker struct {
+	client redis.UniversalClient
+	cfg    *LockerConfig
+}
+
+// NewLocker 創建分散式鎖
+func NewLocker(client redis.UniversalClient, cfg *LockerConfig) *Locker {
+	return &Locker{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// TryLock 嘗試取得鎖，已被持有時立即返回 ErrLockNotAcquired
+// 取得後會在背景自動續期直到 Unlock，續期失敗時 Lost() 會被關閉
+func (l *Locker) TryLock(ctx context.Context, name string, ttl time.Duration) (*Lock, error) {
+	owner, err := newLockOwner()
+	if err != nil {
+		return nil, err
+	}
+
+	key := l.lockKey(name)
+	token, err := acquireScript.Run(ctx, l.client, []string{key, key + keySeparator + "fence"}, owner, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return nil, err
+	}
+	if token == 0 {
+		return nil, ErrLockNotAcquired
+	}
+
+	lock := &Lock{
+		client: l.client,
+		key:    key,
+		owner:  owner,
+		token:  token,
+		ttl:    ttl,
+		lost:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go lock.renew()
+
+	return lock, nil
+}
+
+// Lock 取得鎖，已被持有時每隔 RetryInterval 重試直到 ctx 結束
+func (l *Locker) Lock(ctx context.Context, name string, ttl time.Duration) (*Lock, error) {
+	ticker := time.NewTicker(l.cfg.RetryInterval)
+	defer ticker.Stop()
+
+	for {
+		lock, err := l.TryLock(ctx, name, ttl)
+		if !errors.Is(err, ErrLockNotAcquired) {
+			return lock, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// lockKey 鎖的 key，名稱以 hash tag 包住使鎖與 fencing token 計數器位於同一個 slot
+func (l *Locker) lockKey(name string) string {
+	tagged := "{" + name + "}"
+	if l.cfg.Keys == nil {
+		return "lock" + keySeparator + tagged
+	}
+	return l.cfg.Keys.Key("lock", tagged)
+}
+
+// Lock 已取得的分散式鎖
+type Lock struct {
+	client redis.UniversalClient
+	key    string
+	owner  string
+	token  int64
+	ttl    time.Duration
+
+	once     sync.Once
+	lostOnce sync.Once
+	lost     chan struct{}
+	done     chan struct{}
+}
+
+// Token 取得 fencing token
+func (l *Lock) Token() int64 {
+	return l.token
+}
+
+// Lost 鎖遺失（續期失敗或已過期）時關閉，長時間工作應監聽並中止
+func (l *Lock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Extend 手動延長過期時間
+func (l *Lock) Extend(ctx context.Context, ttl time.Duration) error {
+	ok, err := extendScript.Run(ctx, l.client, []string{l.key}, l.owner, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+	if ok == 0 {
+		l.markLost()
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// Unlock 停止續期並釋放鎖，鎖已不屬於自己時返回 ErrLockNotHeld
+func (l *Lock) Unlock(ctx context.Context) error {
+	l.once.Do(func() { close(l.done) })
+
+	ok, err := releaseScript.Run(ctx, l.client, []string{l.key}, l.owner).Int64()
+	if err != nil {
+		return err
+	}
+	if ok == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// renew 每隔 ttl/3 續期一次，暫時性錯誤會在下次重試，直到鎖確定過期
+func (l *Lock) renew() {
+	interval := l.ttl / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	deadline := time.Now().Add(l.ttl)
+
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		err := l.Extend(ctx, l.ttl)
+		cancel()
+
+		switch {
+		case err == nil:
+			deadline = time.Now().Add(l.ttl)
+		case errors.Is(err, ErrLockNotHeld):
+			logger.Warn("Distributed lock lost", zap.String("key", l.key), zap.Int64("token", l.token))
+			return
+		case time.Now().After(deadline):
+			l.markLost()
+			logger.Warn("Distributed lock expired while renewal failing",
+				zap.String("key", l.key), zap.Int64("token", l.token), zap.Error(err))
+			return
+		default:
+			logger.Warn("Failed to renew distributed lock, retrying", zap.String("key", l.key), zap.Error(err))
+		}
+	}
+}
+
+// markLost 標記鎖已遺失
+func (l *Lock) markLost() {
+	l.lostOnce.Do(func() { close(l.lost) })
+}
+
+// newLockOwner 產生隨機持有者識別碼
+func newLockOwner() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,128 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"sync_drive_backend/internal/core/auth/domain/entity"
+	"sync_drive_backend/internal/core/auth/domain/repository"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// recordFailureScript 記錄一次登入失敗
+// 鎖定開始時歸零失敗次數，鎖定期間的失敗不計數也不延長鎖定，鎖定到期後重新計數
+// KEYS[1]: 節流 hash
+// ARGV[1]: 目前時間（毫秒），ARGV[2]: 不延遲的失敗次數，ARGV[3]: 基礎延遲（毫秒），ARGV[4]: 最大延遲（毫秒）
+// ARGV[5]: 鎖定門檻，ARGV[6]: 鎖定時間（毫秒），ARGV[7]: 計數有效時間（毫秒）
+// 返回 {失敗次數, 下次允許時間（毫秒）, 是否鎖定, 是否由本次失敗觸發鎖定}
+var recordFailureScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local free = tonumber(ARGV[2])
+local threshold = tonumber(ARGV[5])
+
+local state = redis.call('HMGET', KEYS[1], 'failures', 'retry_at', 'locked')
+if state[3] == '1' then
+	local lockedUntil = tonumber(state[2]) or 0
+	if lockedUntil > now then
+		return {tonumber(state[1]) or 0, lockedUntil, 1, 0}
+	end
+	redis.call('HSET', KEYS[1], 'failures', 0)
+end
+
+local n = redis.call('HINCRBY', KEYS[1], 'failures', 1)
+local retry = 0
+local locked = 0
+if threshold > 0 and n >= threshold then
+	retry = now + tonumber(ARGV[6])
+	locked = 1
+	redis.call('HSET', KEYS[1], 'failures', 0)
+elseif n > free then
+	local delay = math.floor(tonumber(ARGV[3]) * (2 ^ (n - free - 1)))
+	retry = now + math.min(delay, tonumber(ARGV[4]))
+end
+redis.call('HSET', KEYS[1], 'retry_at', retry, 'locked', locked)
+redis.call('PEXPIRE', KEYS[1], math.max(tonumber(ARGV[7]), retry - now))
+return {n, retry, locked, locked}
+`)
+
+// LoginAttemptStore 以 Redis 保存登入失敗計數
+type LoginAttemptStore struct {
+	client redis.UniversalClient
+	keys   *KeyBuilder
+}
+
+// 確保實作介面
+var _ repository.ILoginAttemptRepository = (*LoginAttemptStore)(nil)
+
+// NewLoginAttemptStore 創建登入失敗計數儲存
+func NewLoginAttemptStore(client redis.UniversalClient, keys *KeyBuilder) *LoginAttemptStore {
+	return &LoginAttemptStore{
+		client: client,
+		keys:   keys,
+	}
+}
+
+// Get 取得節流狀態
+func (s *LoginAttemptStore) Get(ctx context.Context, subject string) (*entity.LoginThrottle, error) {
+	values, err := s.client.HMGet(ctx, s.attemptKey(subject), "failures", "retry_at", "locked").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	failures, _ := strconv.Atoi(stringValue(values[0]))
+	retryAt, _ := strconv.ParseInt(stringValue(values[1]), 10, 64)
+	return &entity.LoginThrottle{
+		Failures: failures,
+		RetryAt:  fromMillis(retryAt),
+		Locked:   stringValue(values[2]) == "1",
+	}, nil
+}
+
+// RecordFailure 記錄一次失敗
+func (s *LoginAttemptStore) RecordFailure(ctx context.Context, subject string, policy *entity.LockoutPolicy, now time.Time) (*entity.LoginThrottle, error) {
+	result, err := recordFailureScript.Run(ctx, s.client, []string{s.attemptKey(subject)},
+		now.UnixMilli(),
+		policy.FreeAttempts,
+		policy.BaseDelay.Milliseconds(),
+		policy.MaxDelay.Milliseconds(),
+		policy.LockThreshold,
+		policy.LockDuration.Milliseconds(),
+		policy.Window.Milliseconds(),
+	).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.LoginThrottle{
+		Failures:   int(result[0]),
+		RetryAt:    fromMillis(result[1]),
+		Locked:     result[2] == 1,
+		JustLocked: result[3] == 1,
+	}, nil
+}
+
+// Reset 清除失敗紀錄
+func (s *LoginAttemptStore) Reset(ctx context.Context, subject string) error {
+	return s.client.Del(ctx, s.attemptKey(subject)).Err()
+}
+
+// attemptKey 節流 hash 的 key
+func (s *LoginAttemptStore) attemptKey(subject string) string {
+	return s.keys.Key("login_attempts", subject)
+}
+
+// stringValue HMGET 欄位值，不存在時為空字串
+func stringValue(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+// fromMillis 毫秒時間戳轉換為時間，0 為零值
+func fromMillis(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"sync_drive_backend/internal/core/auth/domain/entity"
+	"sync_drive_backend/internal/core/auth/domain/repository"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// attemptChallengeScript 計入一次驗證嘗試
+// KEYS[1]: Challenge hash，ARGV[1]: 最大嘗試次數
+// 返回 {user_id, enroll}，Challenge 不存在或超過次數（同時刪除）時返回 nil
+var attemptChallengeScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return nil
+end
+local n = redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+if n > tonumber(ARGV[1]) then
+	redis.call('DEL', KEYS[1])
+	return nil
+end
+return redis.call('HMGET', KEYS[1], 'user_id', 'enroll')
+`)
+
+// MFAChallengeStore 以 Redis 保存 MFA Challenge
+type MFAChallengeStore struct {
+	client redis.UniversalClient
+	keys   *KeyBuilder
+}
+
+// 確保實作介面
+var _ repository.IMFAChallengeRepository = (*MFAChallengeStore)(nil)
+
+// NewMFAChallengeStore 創建 MFA Challenge 儲存
+func NewMFAChallengeStore(client redis.UniversalClient, keys *KeyBuilder) *MFAChallengeStore {
+	return &MFAChallengeStore{
+		client: client,
+		keys:   keys,
+	}
+}
+
+// Create 建立 Challenge
+func (s *MFAChallengeStore) Create(ctx context.Context, tokenHash string, challenge *entity.MFAChallenge, ttl time.Duration) error {
+	key := s.challengeKey(tokenHash)
+
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "user_id", challenge.UserID, "enroll", boolToFlag(challenge.Enrollment), "attempts", 0)
+		pipe.PExpire(ctx, key, ttl)
+		return nil
+	})
+	return err
+}
+
+// Get 取得 Challenge
+func (s *MFAChallengeStore) Get(ctx context.Context, tokenHash string) (*entity.MFAChallenge, error) {
+	values, err := s.client.HMGet(ctx, s.challengeKey(tokenHash), "user_id", "enroll").Result()
+	if err != nil {
+		return nil, err
+	}
+	return toMFAChallenge(values)
+}
+
+// Attempt 計入一次驗證嘗試並取得 Challenge
+func (s *MFAChallengeStore) Attempt(ctx context.Context, tokenHash string, maxAttempts int) (*entity.MFAChallenge, error) {
+	values, err := attemptChallengeScript.Run(ctx, s.client, []string{s.challengeKey(tokenHash)}, maxAttempts).Slice()
+	if err == redis.Nil {
+		return nil, repository.ErrMFAChallengeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toMFAChallenge(values)
+}
+
+// Delete 刪除 Challenge
+func (s *MFAChallengeStore) Delete(ctx context.Context, tokenHash string) error {
+	return s.client.Del(ctx, s.challengeKey(tokenHash)).Err()
+}
+
+// challengeKey Challenge 的 key
+func (s *MFAChallengeStore) challengeKey(tokenHash string) string {
+	return s.keys.Key("mfa_challenge", tokenHash)
+}
+
+// toMFAChallenge 轉換 HMGET 結果
+func toMFAChallenge(values []interface{}) (*entity.MFAChallenge, error) {
+	userID, _ := values[0].(string)
+	if userID == "" {
+		return nil, repository.ErrMFAChallengeNotFound
+	}
+	enroll, _ := values[1].(string)
+
+	return &entity.MFAChallenge{
+		UserID:     userID,
+		Enrollment: enroll == "1",
+	}, nil
+}
+
+// boolToFlag 布林值轉為 hash 欄位值
+func boolToFlag(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
@@ -0,0 +1,60 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"sync_drive_backend/internal/core/auth/domain/entity"
+	"sync_drive_backend/internal/core/auth/domain/repository"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// OIDCStateStore 以 Redis 保存 OIDC 授權請求的 state、nonce 與 PKCE code_verifier
+type OIDCStateStore struct {
+	client redis.UniversalClient
+	keys   *KeyBuilder
+}
+
+// 確保實作介面
+var _ repository.IOIDCStateRepository = (*OIDCStateStore)(nil)
+
+// NewOIDCStateStore 創建 OIDC state 儲存
+func NewOIDCStateStore(client redis.UniversalClient, keys *KeyBuilder) *OIDCStateStore {
+	return &OIDCStateStore{
+		client: client,
+		keys:   keys,
+	}
+}
+
+// Save 保存 state
+func (s *OIDCStateStore) Save(ctx context.Context, state string, data *entity.OIDCState, ttl time.Duration) error {
+	value, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, s.stateKey(state), value, ttl).Err()
+}
+
+// Consume 取出並刪除 state，每個 state 只能使用一次
+func (s *OIDCStateStore) Consume(ctx context.Context, state string) (*entity.OIDCState, error) {
+	value, err := s.client.GetDel(ctx, s.stateKey(state)).Bytes()
+	if err == redis.Nil {
+		return nil, repository.ErrOIDCStateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var data entity.OIDCState
+	if err := json.Unmarshal(value, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// stateKey state 的 key
+func (s *OIDCStateStore) stateKey(state string) string {
+	return s.keys.Key("oidc_state", state)
+}
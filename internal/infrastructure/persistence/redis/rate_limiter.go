@@ -0,0 +1,133 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sync_drive_backend/internal/common/ratelimit"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScript Token Bucket 限流，以 Redis 伺服器時間計算避免副本間時鐘誤差
+// KEYS[1]: bucket（hash：tokens, ts）
+// ARGV[1]: 容量，ARGV[2]: 每個窗口補充的 token 數，ARGV[3]: 窗口（毫秒）
+// 返回 {是否允許, 剩餘 token, 重試等待毫秒, 補滿所需毫秒}
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2]) / tonumber(ARGV[3])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or capacity
+local ts = tonumber(state[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+
+local reset = math.ceil((capacity - tokens) / rate)
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], reset + 1000)
+
+return {allowed, math.floor(tokens), retry, reset}
+`)
+
+// slidingWindowScript Sliding Window Counter 限流，前後兩個窗口的計數存放於同一個 hash
+// KEYS[1]: 計數（hash：窗口編號 → 請求數）
+// ARGV[1]: 上限，ARGV[2]: 窗口（毫秒）
+// 返回 {是否允許, 剩餘次數, 重試等待毫秒, 窗口重置毫秒}
+var slidingWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local index = math.floor(now / window)
+local elapsed = now - index * window
+local counts = redis.call('HMGET', KEYS[1], tostring(index - 1), tostring(index))
+local previous = tonumber(counts[1]) or 0
+local current = tonumber(counts[2]) or 0
+local estimated = previous * (window - elapsed) / window + current
+local reset = window - elapsed
+
+if estimated + 1 > limit then
+	local retry = reset
+	if current < limit and previous > 0 then
+		-- 上一個窗口的權重下降到足以容納一次請求的時間點
+		retry = math.max(1, math.ceil(window - (limit - 1 - current) * window / previous - elapsed))
+	end
+	return {0, 0, retry, reset}
+end
+
+redis.call('HINCRBY', KEYS[1], tostring(index), 1)
+if redis.call('HLEN', KEYS[1]) > 2 then
+	for _, field in ipairs(redis.call('HKEYS', KEYS[1])) do
+		local n = tonumber(field)
+		if n ~= index and n ~= index - 1 then
+			redis.call('HDEL', KEYS[1], field)
+		end
+	end
+end
+redis.call('PEXPIRE', KEYS[1], window * 2)
+
+return {1, math.floor(limit - estimated - 1), 0, reset}
+`)
+
+// RateLimiter Redis 分散式限流器，所有副本共用同一份額度
+type RateLimiter struct {
+	client redis.UniversalClient
+	keys   *KeyBuilder
+}
+
+// 確保實作介面
+var _ ratelimit.Limiter = (*RateLimiter)(nil)
+
+// NewRateLimiter 創建 Redis 限流器
+func NewRateLimiter(client redis.UniversalClient, keys *KeyBuilder) *RateLimiter {
+	return &RateLimiter{
+		client: client,
+		keys:   keys,
+	}
+}
+
+// Allow 檢查是否允許請求
+func (r *RateLimiter) Allow(ctx context.Context, key string, policy *ratelimit.Policy) (*ratelimit.Result, error) {
+	redisKey := r.keys.Key("ratelimit", policy.Name, key)
+	window := policy.Window.Milliseconds()
+
+	var values []int64
+	var err error
+	switch policy.Algorithm {
+	case ratelimit.AlgorithmTokenBucket:
+		values, err = tokenBucketScript.Run(ctx, r.client, []string{redisKey}, policy.Capacity(), policy.Limit, window).Int64Slice()
+	case ratelimit.AlgorithmSlidingWindow, "":
+		values, err = slidingWindowScript.Run(ctx, r.client, []string{redisKey}, policy.Limit, window).Int64Slice()
+	default:
+		return nil, fmt.Errorf("unsupported rate limit algorithm: %q", policy.Algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	limit := policy.Limit
+	if policy.Algorithm == ratelimit.AlgorithmTokenBucket {
+		limit = policy.Capacity()
+	}
+
+	return &ratelimit.Result{
+		Allowed:    values[0] == 1,
+		Limit:      limit,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		ResetAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,109 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sync_drive_backend/internal/common/consts"
+	"sync_drive_backend/internal/core/auth/domain/entity"
+	"sync_drive_backend/internal/core/auth/domain/repository"
+	apperrors "sync_drive_backend/pkg/errors"
+	"sync_drive_backend/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// roleCacheTag 所有角色快取共用的標籤，任何角色或權限異動時整批失效
+const roleCacheTag = "auth:roles"
+
+// CachedRoleRepository 以兩級快取包裝角色儲存庫
+// 每個請求的權限檢查都會查詢角色，FindRole 優先讀取本地 LRU；異動後透過標籤與 Pub/Sub 通知所有副本失效
+type CachedRoleRepository struct {
+	repository.IRoleRepository
+	cache *TwoLevelCache
+	keys  *KeyBuilder
+	ttl   time.Duration
+}
+
+// 確保實作介面
+var _ repository.IRoleRepository = (*CachedRoleRepository)(nil)
+
+// NewCachedRoleRepository 創建快取角色儲存庫
+func NewCachedRoleRepository(inner repository.IRoleRepository, cache *TwoLevelCache, keys *KeyBuilder, ttl time.Duration) *CachedRoleRepository {
+	return &CachedRoleRepository{
+		IRoleRepository: inner,
+		cache:           cache,
+		keys:            keys,
+		ttl:             ttl,
+	}
+}
+
+// FindRole 依名稱查詢（快取）
+func (r *CachedRoleRepository) FindRole(ctx context.Context, name consts.Role) (*entity.Role, error) {
+	role, err := TwoLevelGetOrLoad(ctx, r.cache, r.keys.Key("role", name.String()), r.ttl, []string{roleCacheTag},
+		func(ctx context.Context) (*entity.Role, error) {
+			role, err := r.IRoleRepository.FindRole(ctx, name)
+			if errors.Is(err, repository.ErrRoleNotFound) {
+				// 寫入負向快取，避免以不存在的角色反覆查詢資料庫
+				return nil, apperrors.Wrap(apperrors.ErrNotFound, "role not found", err)
+			}
+			return role, err
+		})
+	if apperrors.IsCode(err, apperrors.ErrNotFound) {
+		return nil, repository.ErrRoleNotFound
+	}
+	return role, err
+}
+
+// CreateRole 建立角色，並清除可能存在的負向快取
+func (r *CachedRoleRepository) CreateRole(ctx context.Context, role *entity.Role) error {
+	if err := r.IRoleRepository.CreateRole(ctx, role); err != nil {
+		return err
+	}
+	r.invalidate(ctx)
+	return nil
+}
+
+// UpdateRole 更新角色說明
+func (r *CachedRoleRepository) UpdateRole(ctx context.Context, role *entity.Role) error {
+	if err := r.IRoleRepository.UpdateRole(ctx, role); err != nil {
+		return err
+	}
+	r.invalidate(ctx)
+	return nil
+}
+
+// DeleteRole 刪除角色
+func (r *CachedRoleRepository) DeleteRole(ctx context.Context, name consts.Role) error {
+	if err := r.IRoleRepository.DeleteRole(ctx, name); err != nil {
+		return err
+	}
+	r.invalidate(ctx)
+	return nil
+}
+
+// SetRolePermissions 取代角色的全部權限
+func (r *CachedRoleRepository) SetRolePermissions(ctx context.Context, name consts.Role, permissions []consts.Permission) error {
+	if err := r.IRoleRepository.SetRolePermissions(ctx, name, permissions); err != nil {
+		return err
+	}
+	r.invalidate(ctx)
+	return nil
+}
+
+// DeletePermission 刪除權限（同時自所有角色移除）
+func (r *CachedRoleRepository) DeletePermission(ctx context.Context, code consts.Permission) error {
+	if err := r.IRoleRepository.DeletePermission(ctx, code); err != nil {
+		return err
+	}
+	r.invalidate(ctx)
+	return nil
+}
+
+// invalidate 清除所有角色快取，失敗時僅記錄日誌（異動已寫入，快取最遲於 TTL 到期後更新）
+func (r *CachedRoleRepository) invalidate(ctx context.Context) {
+	if _, err := r.cache.InvalidateTags(ctx, roleCacheTag); err != nil {
+		logger.Warn("Failed to invalidate role cache", zap.Error(err))
+	}
+}
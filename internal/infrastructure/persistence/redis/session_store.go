@@ -0,0 +1,309 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"sync_drive_backend/internal/core/auth/domain/entity"
+	"sync_drive_backend/internal/core/auth/domain/repository"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 同一用戶的 key 都帶有 hash tag {userID}，Cluster 模式下位於同一個 slot，可在同一個 Lua 腳本中操作
+
+// rotateSessionScript 輪替 Refresh Token
+// KEYS[1]: Session hash，KEYS[2]: 用戶 Session 索引
+// ARGV[1]: 出示的 jti，ARGV[2]: 新的 jti，ARGV[3]: 新 Access Token jti，ARGV[4]: Access Token 到期時間（毫秒）
+// ARGV[5]: IP，ARGV[6]: 最後使用時間（毫秒），ARGV[7]: 有效期（毫秒），ARGV[8]: Session ID
+// 返回 {1} 成功，{0} Session 不存在，{-1, access, access_exp} 重複使用（Session 已刪除，需將 access 加入黑名單）
+var rotateSessionScript = redis.NewScript(`
+local s = redis.call('HMGET', KEYS[1], 'current', 'access', 'access_exp')
+if not s[1] then
+	return {0}
+end
+if s[1] ~= ARGV[1] then
+	redis.call('DEL', KEYS[1])
+	redis.call('SREM', KEYS[2], ARGV[8])
+	return {-1, s[2] or '', s[3] or '0'}
+end
+redis.call('HSET', KEYS[1], 'current', ARGV[2], 'access', ARGV[3], 'access_exp', ARGV[4], 'ip', ARGV[5], 'last_seen', ARGV[6])
+redis.call('PEXPIRE', KEYS[1], ARGV[7])
+if redis.call('PTTL', KEYS[2]) < tonumber(ARGV[7]) then
+	redis.call('PEXPIRE', KEYS[2], ARGV[7])
+end
+return {1}
+`)
+
+// checkAccessScript 檢查 Access Token 是否已撤銷
+// KEYS[1]: 黑名單，KEYS[2]: 用戶撤銷時間，KEYS[3]（可選）: Session hash
+// ARGV[1]: Token 簽發時間（秒），ARGV[2]: 目前時間（毫秒）
+// 返回 1 已撤銷，0 有效
+var checkAccessScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 1
+end
+local before = redis.call('GET', KEYS[2])
+if before and tonumber(ARGV[1]) < tonumber(before) then
+	return 1
+end
+if KEYS[3] and redis.call('EXISTS', KEYS[3]) == 1 then
+	redis.call('HSET', KEYS[3], 'last_seen', ARGV[2])
+end
+return 0
+`)
+
+// SessionStore 以 Redis 保存登入 Session、Access Token 黑名單與用戶撤銷時間
+type SessionStore struct {
+	client redis.UniversalClient
+	keys   *KeyBuilder
+}
+
+// 確保實作介面
+var _ repository.ISessionRepository = (*SessionStore)(nil)
+
+// NewSessionStore 創建 Session 儲存
+func NewSessionStore(client redis.UniversalClient, keys *KeyBuilder) *SessionStore {
+	return &SessionStore{
+		client: client,
+		keys:   keys,
+	}
+}
+
+// Create 建立 Session
+func (s *SessionStore) Create(ctx context.Context, session *entity.Session, ttl time.Duration) error {
+	key := s.sessionKey(session.UserID, session.ID)
+	index := s.indexKey(session.UserID)
+
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key,
+			"user_id", session.UserID,
+			"current", session.CurrentTokenID,
+			"access", session.AccessTokenID,
+			"access_exp", session.AccessExpiresAt.UnixMilli(),
+			"device_id", session.DeviceID,
+			"user_agent", session.UserAgent,
+			"ip", session.IP,
+			"created_at", session.CreatedAt.UnixMilli(),
+			"last_seen", session.LastSeenAt.UnixMilli(),
+		)
+		pipe.PExpire(ctx, key, ttl)
+		pipe.SAdd(ctx, index, session.ID)
+		pipe.PExpire(ctx, index, ttl)
+		return nil
+	})
+	return err
+}
+
+// Rotate 輪替 Refresh Token
+func (s *SessionStore) Rotate(ctx context.Context, session *entity.Session, presentedTokenID string, ttl time.Duration) error {
+	keys := []string{s.sessionKey(session.UserID, session.ID), s.indexKey(session.UserID)}
+	result, err := rotateSessionScript.Run(ctx, s.client, keys,
+		presentedTokenID,
+		session.CurrentTokenID,
+		session.AccessTokenID,
+		session.AccessExpiresAt.UnixMilli(),
+		session.IP,
+		session.LastSeenAt.UnixMilli(),
+		ttl.Milliseconds(),
+		session.ID,
+	).Slice()
+	if err != nil {
+		return err
+	}
+
+	switch result[0].(int64) {
+	case 0:
+		return repository.ErrSessionNotFound
+	case -1:
+		// 被盜用的 Session 可能仍持有有效的 Access Token，一併加入黑名單
+		accessID, _ := result[1].(string)
+		accessExp, _ := strconv.ParseInt(fmt.Sprint(result[2]), 10, 64)
+		if err := s.deny(ctx, s.client, session.UserID, accessID, time.UnixMilli(accessExp)); err != nil {
+			return err
+		}
+		return repository.ErrRefreshTokenReused
+	default:
+		return nil
+	}
+}
+
+// List 取得用戶所有有效的 Session，依最後使用時間倒序
+func (s *SessionStore) List(ctx context.Context, userID string) ([]*entity.Session, error) {
+	index := s.indexKey(userID)
+
+	ids, err := s.client.SMembers(ctx, index).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	cmds := make([]*redis.MapStringStringCmd, len(ids))
+	_, err = s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, id := range ids {
+			cmds[i] = pipe.HGetAll(ctx, s.sessionKey(userID, id))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*entity.Session, 0, len(ids))
+	var expired []interface{}
+	for i, cmd := range cmds {
+		fields := cmd.Val()
+		if len(fields) == 0 {
+			// Session 已過期，索引尚未清除
+			expired = append(expired, ids[i])
+			continue
+		}
+		sessions = append(sessions, toSession(ids[i], fields))
+	}
+
+	if len(expired) > 0 {
+		_ = s.client.SRem(ctx, index, expired...).Err()
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+	return sessions, nil
+}
+
+// Revoke 撤銷 Session
+func (s *SessionStore) Revoke(ctx context.Context, userID, sessionID string) error {
+	key := s.sessionKey(userID, sessionID)
+
+	fields, err := s.client.HMGet(ctx, key, "access", "access_exp").Result()
+	if err != nil {
+		return err
+	}
+	if fields[0] == nil {
+		return repository.ErrSessionNotFound
+	}
+
+	accessID, _ := fields[0].(string)
+	accessExp, _ := strconv.ParseInt(fmt.Sprint(fields[1]), 10, 64)
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.SRem(ctx, s.indexKey(userID), sessionID)
+		return s.deny(ctx, pipe, userID, accessID, time.UnixMilli(accessExp))
+	})
+	return err
+}
+
+// RevokeAll 撤銷用戶所有 Session
+// revoked_before 以秒記錄且比對 iat < before，同一秒內簽發的 Access Token 不受影響，因此另將各 Session 目前的 Access Token 加入黑名單
+func (s *SessionStore) RevokeAll(ctx context.Context, userID string, before time.Time, ttl time.Duration) error {
+	index := s.indexKey(userID)
+
+	ids, err := s.client.SMembers(ctx, index).Result()
+	if err != nil {
+		return err
+	}
+
+	cmds := make([]*redis.SliceCmd, len(ids))
+	if len(ids) > 0 {
+		_, err = s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for i, id := range ids {
+				cmds[i] = pipe.HMGet(ctx, s.sessionKey(userID, id), "access", "access_exp")
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, s.revokedBeforeKey(userID), before.Unix(), ttl)
+		for i, id := range ids {
+			pipe.Del(ctx, s.sessionKey(userID, id))
+
+			fields := cmds[i].Val()
+			if len(fields) < 2 || fields[0] == nil {
+				continue // Session 已過期
+			}
+			accessID, _ := fields[0].(string)
+			accessExp, _ := strconv.ParseInt(fmt.Sprint(fields[1]), 10, 64)
+			if err := s.deny(ctx, pipe, userID, accessID, time.UnixMilli(accessExp)); err != nil {
+				return err
+			}
+		}
+		pipe.Del(ctx, index)
+		return nil
+	})
+	return err
+}
+
+// IsAccessRevoked 檢查 Access Token 是否已撤銷
+func (s *SessionStore) IsAccessRevoked(ctx context.Context, userID, sessionID, tokenID string, issuedAt, now time.Time) (bool, error) {
+	keys := []string{s.denyKey(userID, tokenID), s.revokedBeforeKey(userID)}
+	if sessionID != "" {
+		keys = append(keys, s.sessionKey(userID, sessionID))
+	}
+
+	revoked, err := checkAccessScript.Run(ctx, s.client, keys, issuedAt.Unix(), now.UnixMilli()).Int()
+	if err != nil {
+		return false, err
+	}
+	return revoked == 1, nil
+}
+
+// deny 將 Access Token 加入黑名單直到到期，已到期的 Token 不需要記錄
+func (s *SessionStore) deny(ctx context.Context, cmd redis.Cmdable, userID, tokenID string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if tokenID == "" || ttl <= 0 {
+		return nil
+	}
+	return cmd.Set(ctx, s.denyKey(userID, tokenID), 1, ttl).Err()
+}
+
+// sessionKey Session 的 key
+func (s *SessionStore) sessionKey(userID, sessionID string) string {
+	return s.keys.Key("session", "{"+userID+"}", sessionID)
+}
+
+// indexKey 用戶 Session 索引的 key
+func (s *SessionStore) indexKey(userID string) string {
+	return s.keys.Key("sessions", "{"+userID+"}")
+}
+
+// denyKey Access Token 黑名單的 key
+func (s *SessionStore) denyKey(userID, tokenID string) string {
+	return s.keys.Key("denylist", "{"+userID+"}", tokenID)
+}
+
+// revokedBeforeKey 用戶撤銷時間的 key，早於此時間簽發的 Token 全部失效
+func (s *SessionStore) revokedBeforeKey(userID string) string {
+	return s.keys.Key("revoked_before", "{"+userID+"}")
+}
+
+// toSession 將 hash 欄位轉換為 Session
+func toSession(id string, fields map[string]string) *entity.Session {
+	return &entity.Session{
+		ID:              id,
+		UserID:          fields["user_id"],
+		CurrentTokenID:  fields["current"],
+		AccessTokenID:   fields["access"],
+		AccessExpiresAt: parseUnixMilli(fields["access_exp"]),
+		DeviceID:        fields["device_id"],
+		UserAgent:       fields["user_agent"],
+		IP:              fields["ip"],
+		CreatedAt:       parseUnixMilli(fields["created_at"]),
+		LastSeenAt:      parseUnixMilli(fields["last_seen"]),
+	}
+}
+
+// parseUnixMilli 解析毫秒時間戳
+func parseUnixMilli(value string) time.Time {
+	ms, _ := strconv.ParseInt(value, 10, 64)
+	return time.UnixMilli(ms)
+}
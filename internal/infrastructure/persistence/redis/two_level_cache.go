@@ -0,0 +1,227 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"sync_drive_backend/pkg/logger"
+	"sync_drive_backend/pkg/lru"
+	"sync_drive_backend/pkg/tools"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// TwoLevelConfig 兩級快取配置
+type TwoLevelConfig struct {
+	LocalSize int           // 本地 LRU 最大項目數量
+	LocalTTL  time.Duration // 本地項目存活時間，同時是未收到失效通知時的最長不一致時間
+	Channel   string        // 跨副本失效通知的 Pub/Sub channel
+}
+
+// localEntry 本地快取項目（保存 JSON 以避免呼叫端共用同一個物件）
+type localEntry struct {
+	data []byte
+	tags []string
+}
+
+// invalidation 跨副本失效通知
+type invalidation struct {
+	Origin string   `json:"origin"`
+	Keys   []string `json:"keys,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+}
+
+// TwoLevelCache 兩級快取：本地 LRU + Redis
+// 適合讀多寫少的熱點資料（角色權限、車輛資訊），寫入或失效時透過 Pub/Sub 通知所有副本移除本地項目
+type TwoLevelCache struct {
+	remote     *Cache
+	client     redis.UniversalClient
+	local      *lru.Cache[string, *localEntry]
+	channel    string
+	instanceID string
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewTwoLevelCache 創建兩級快取
+func NewTwoLevelCache(remote *Cache, client redis.UniversalClient, cfg *TwoLevelConfig) *TwoLevelCache {
+	return &TwoLevelCache{
+		remote:     remote,
+		client:     client,
+		local:      lru.New[string, *localEntry](cfg.LocalSize, cfg.LocalTTL),
+		channel:    cfg.Channel,
+		instanceID: tools.GenerateUUID(),
+	}
+}
+
+// Start 訂閱跨副本失效通知
+func (t *TwoLevelCache) Start() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+	t.done = make(chan struct{})
+
+	go t.subscribe(ctx)
+}
+
+// Stop 停止訂閱
+func (t *TwoLevelCache) Stop(ctx context.Context) error {
+	t.mu.Lock()
+	cancel, done := t.cancel, t.done
+	t.cancel = nil
+	t.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Set 寫入快取並通知其他副本移除本地項目
+func (t *TwoLevelCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags ...string) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache %s: %w", key, err)
+	}
+
+	if err := t.remote.SetWithTags(ctx, key, b, t.remote.jitter(ttl), tags...); err != nil {
+		return err
+	}
+
+	t.local.Set(key, &localEntry{data: b, tags: tags})
+	return t.publish(ctx, &invalidation{Keys: []string{key}})
+}
+
+// Del 刪除快取並通知其他副本
+func (t *TwoLevelCache) Del(ctx context.Context, keys ...string) error {
+	t.local.Remove(keys...)
+
+	if err := t.remote.Del(ctx, keys...); err != nil {
+		return err
+	}
+
+	return t.publish(ctx, &invalidation{Keys: keys})
+}
+
+// InvalidateTags 刪除標籤關聯的快取並通知其他副本
+func (t *TwoLevelCache) InvalidateTags(ctx context.Context, tags ...string) (int64, error) {
+	t.evictTags(tags)
+
+	deleted, err := t.remote.InvalidateTags(ctx, tags...)
+	if err != nil {
+		return 0, err
+	}
+
+	return deleted, t.publish(ctx, &invalidation{Tags: tags})
+}
+
+// TwoLevelGetOrLoad 依序讀取本地 LRU、Redis，皆未命中時呼叫 loader
+// Redis 層的行為（singleflight、負向快取、降級）同 GetOrLoadWithTags，負向結果不寫入本地
+func TwoLevelGetOrLoad[T any](ctx context.Context, t *TwoLevelCache, key string, ttl time.Duration, tags []string, loader Loader[T]) (T, error) {
+	if e, ok := t.local.Get(key); ok {
+		var value T
+		if err := json.Unmarshal(e.data, &value); err == nil {
+			return value, nil
+		}
+		t.local.Remove(key)
+	}
+
+	value, err := GetOrLoadWithTags(ctx, t.remote, key, ttl, tags, loader)
+	if err != nil {
+		return value, err
+	}
+
+	if b, err := json.Marshal(value); err == nil {
+		t.local.Set(key, &localEntry{data: b, tags: tags})
+	}
+
+	return value, nil
+}
+
+// publish 發佈失效通知
+func (t *TwoLevelCache) publish(ctx context.Context, msg *invalidation) error {
+	msg.Origin = t.instanceID
+
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return t.client.Publish(ctx, t.channel, b).Err()
+}
+
+// subscribe 接收失效通知，斷線期間的通知會遺失，因此重新訂閱後清空本地快取
+func (t *TwoLevelCache) subscribe(ctx context.Context) {
+	defer close(t.done)
+
+	pubsub := t.client.Subscribe(ctx, t.channel)
+	defer pubsub.Close()
+
+	for {
+		msg, err := pubsub.ReceiveMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			logger.Warn("Cache invalidation subscription interrupted", zap.Error(err))
+			t.local.Purge()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		var inv invalidation
+		if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil {
+			logger.Warn("Invalid cache invalidation message", zap.Error(err))
+			continue
+		}
+		if inv.Origin == t.instanceID {
+			continue
+		}
+
+		t.local.Remove(inv.Keys...)
+		t.evictTags(inv.Tags)
+	}
+}
+
+// evictTags 移除本地關聯指定標籤的項目
+func (t *TwoLevelCache) evictTags(tags []string) {
+	if len(tags) == 0 {
+		return
+	}
+
+	t.local.RemoveFunc(func(_ string, e *localEntry) bool {
+		for _, tag := range e.tags {
+			for _, target := range tags {
+				if tag == target {
+					return true
+				}
+			}
+		}
+		return false
+	})
+}
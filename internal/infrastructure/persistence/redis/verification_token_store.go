@@ -0,0 +1,79 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"sync_drive_backend/internal/core/auth/domain/entity"
+	"sync_drive_backend/internal/core/auth/domain/repository"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// VerificationTokenStore 以 Redis 保存一次性驗證 Token
+// Token key 以雜湊命名，另以用戶 key 記錄目前有效的 Token，簽發新 Token 時使舊的失效
+type VerificationTokenStore struct {
+	client redis.UniversalClient
+	keys   *KeyBuilder
+}
+
+// 確保實作介面
+var _ repository.IVerificationTokenRepository = (*VerificationTokenStore)(nil)
+
+// NewVerificationTokenStore 創建驗證 Token 儲存
+func NewVerificationTokenStore(client redis.UniversalClient, keys *KeyBuilder) *VerificationTokenStore {
+	return &VerificationTokenStore{
+		client: client,
+		keys:   keys,
+	}
+}
+
+// Save 保存 Token 雜湊
+func (s *VerificationTokenStore) Save(ctx context.Context, purpose entity.TokenPurpose, tokenHash, userID string, ttl time.Duration) error {
+	userKey := s.userKey(purpose, userID)
+
+	previous, err := s.client.Get(ctx, userKey).Result()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+
+	// Token key 與用戶 key 不在同一個 slot，使用一般 pipeline（Cluster 模式會依 slot 拆分）
+	_, err = s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		if previous != "" {
+			pipe.Del(ctx, s.tokenKey(purpose, previous))
+		}
+		pipe.Set(ctx, s.tokenKey(purpose, tokenHash), userID, ttl)
+		pipe.Set(ctx, userKey, tokenHash, ttl)
+		return nil
+	})
+	return err
+}
+
+// Consume 取出並刪除 Token
+func (s *VerificationTokenStore) Consume(ctx context.Context, purpose entity.TokenPurpose, tokenHash string) (string, error) {
+	userID, err := s.client.GetDel(ctx, s.tokenKey(purpose, tokenHash)).Result()
+	if err == redis.Nil {
+		return "", repository.ErrVerificationTokenNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	_ = s.client.Del(ctx, s.userKey(purpose, userID)).Err()
+	return userID, nil
+}
+
+// AcquireCooldown 以 SET NX 取得寄送冷卻
+func (s *VerificationTokenStore) AcquireCooldown(ctx context.Context, purpose entity.TokenPurpose, subject string, ttl time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, s.keys.Key("verification", string(purpose), "cooldown", subject), 1, ttl).Result()
+}
+
+// tokenKey Token 的 key
+func (s *VerificationTokenStore) tokenKey(purpose entity.TokenPurpose, tokenHash string) string {
+	return s.keys.Key("verification", string(purpose), tokenHash)
+}
+
+// userKey 用戶目前有效 Token 的 key
+func (s *VerificationTokenStore) userKey(purpose entity.TokenPurpose, userID string) string {
+	return s.keys.Key("verification", string(purpose), "user", userID)
+}
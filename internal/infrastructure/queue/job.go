@@ -0,0 +1,106 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Priority 工作優先順序，數值越小越優先
+type Priority int
+
+const (
+	PriorityHigh    Priority = 0
+	PriorityDefault Priority = 1
+	PriorityLow     Priority = 2
+)
+
+// priorities 依優先順序排列
+var priorities = []Priority{PriorityHigh, PriorityDefault, PriorityLow}
+
+// Job 工作內容，以 JSON 存放於 Stream 與延遲佇列
+type Job struct {
+	ID          string          `json:"id"`
+	Queue       string          `json:"queue"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Priority    Priority        `json:"priority"`
+	Attempts    int             `json:"attempts"`     // 已失敗次數
+	MaxAttempts int             `json:"max_attempts"` // 最大執行次數，超過後移入 dead-letter
+	LastError   string          `json:"last_error,omitempty"`
+	EnqueuedAt  time.Time       `json:"enqueued_at"`
+}
+
+// Handler 工作處理函數，返回錯誤時依退避時間重試
+// 工作可能因副本中斷而重複執行，處理邏輯必須冪等
+type Handler func(ctx context.Context, job *Job) error
+
+// EnqueueOption 加入工作的選項
+type EnqueueOption func(*Job, *time.Duration)
+
+// WithDelay 延遲執行
+func WithDelay(delay time.Duration) EnqueueOption {
+	return func(_ *Job, d *time.Duration) {
+		*d = delay
+	}
+}
+
+// WithPriority 設定優先順序
+func WithPriority(priority Priority) EnqueueOption {
+	return func(job *Job, _ *time.Duration) {
+		job.Priority = priority
+	}
+}
+
+// WithMaxAttempts 設定最大執行次數（覆蓋 queue.maxAttempts）
+func WithMaxAttempts(n int) EnqueueOption {
+	return func(job *Job, _ *time.Duration) {
+		job.MaxAttempts = n
+	}
+}
+
+// JobType 具型別的工作定義，綁定佇列、工作類型與 payload 型別
+//
+//	var SendNotification = queue.NewJobType[NotificationPayload]("notifications", "notification.send")
+//
+//	queue.Handle(q, SendNotification, func(ctx context.Context, p NotificationPayload) error { ... })
+//	queue.Enqueue(ctx, q, SendNotification, NotificationPayload{...}, queue.WithDelay(time.Minute))
+type JobType[T any] struct {
+	Queue string
+	Name  string
+}
+
+// NewJobType 創建具型別的工作定義
+func NewJobType[T any](queue, name string) JobType[T] {
+	return JobType[T]{Queue: queue, Name: name}
+}
+
+// Handle 註冊具型別的處理函數，payload 反序列化失敗時直接移入 dead-letter
+func Handle[T any](q *Queue, jt JobType[T], fn func(ctx context.Context, payload T) error) {
+	q.Register(jt.Queue, jt.Name, func(ctx context.Context, job *Job) error {
+		var payload T
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return Permanent(fmt.Errorf("failed to unmarshal payload: %w", err))
+		}
+		return fn(ctx, payload)
+	})
+}
+
+// Enqueue 加入具型別的工作，返回工作 ID
+func Enqueue[T any](ctx context.Context, q *Queue, jt JobType[T], payload T, opts ...EnqueueOption) (string, error) {
+	return q.Enqueue(ctx, jt.Queue, jt.Name, payload, opts...)
+}
+
+// permanentError 不需重試的錯誤
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent 標記錯誤不需重試，工作直接移入 dead-letter
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
@@ -0,0 +1,184 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	redisinfra "sync_drive_backend/internal/infrastructure/persistence/redis"
+	"sync_drive_backend/pkg/tools"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// consumerGroup 所有副本共用的 consumer group
+const consumerGroup = "workers"
+
+// Config 工作佇列配置
+type Config struct {
+	Concurrency       map[string]int // 佇列名稱 → 同時處理的工作數量，只會消費列出的佇列
+	Block             time.Duration  // 等待新工作的最長時間
+	VisibilityTimeout time.Duration  // 工作超過此時間未確認視為副本中斷，由其他副本重新領取
+	ReclaimInterval   time.Duration  // 檢查中斷工作的間隔
+	PromoteInterval   time.Duration  // 延遲工作移入 stream 的檢查間隔
+	MaxAttempts       int            // 預設最大執行次數
+	RetryBackoff      time.Duration  // 重試基礎退避時間（指數成長）
+	MaxBackoff        time.Duration  // 重試退避上限
+	StreamMaxLen      int64          // stream 保留的大約訊息數量上限（0 表示不限制）
+}
+
+// Queue 以 Redis Streams 實作的背景工作佇列
+// 每個佇列依優先順序分為三個 stream，以 consumer group 在副本間分配工作；
+// 延遲與重試的工作存放於 zset，到期後移入 stream；超過最大次數的工作移入 dead-letter stream
+type Queue struct {
+	client   redis.UniversalClient
+	keys     *redisinfra.KeyBuilder
+	cfg      *Config
+	consumer string
+
+	mu       sync.Mutex
+	handlers map[string]Handler // 工作類型 → 處理函數
+	cancel   context.CancelFunc // 停止領取新工作
+	abort    context.CancelFunc // 中止處理中的工作
+	wg       sync.WaitGroup     // 領取迴圈
+	jobs     sync.WaitGroup     // 處理中的工作
+}
+
+// New 創建工作佇列
+func New(client redis.UniversalClient, keys *redisinfra.KeyBuilder, cfg *Config) *Queue {
+	hostname, _ := os.Hostname()
+
+	return &Queue{
+		client:   client,
+		keys:     keys,
+		cfg:      cfg,
+		consumer: hostname + "-" + tools.GenerateUUID()[:8],
+		handlers: make(map[string]Handler),
+	}
+}
+
+// Register 註冊工作類型的處理函數，需在 Start 之前呼叫
+func (q *Queue) Register(queue, jobType string, handler Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.handlers[handlerKey(queue, jobType)] = handler
+}
+
+// Enqueue 加入工作，返回工作 ID
+func (q *Queue) Enqueue(ctx context.Context, queue, jobType string, payload interface{}, opts ...EnqueueOption) (string, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal job payload: %w", err)
+	}
+
+	job := &Job{
+		ID:          tools.GenerateUUID(),
+		Queue:       queue,
+		Type:        jobType,
+		Payload:     body,
+		Priority:    PriorityDefault,
+		MaxAttempts: q.cfg.MaxAttempts,
+		EnqueuedAt:  time.Now(),
+	}
+	var delay time.Duration
+	for _, opt := range opts {
+		opt(job, &delay)
+	}
+	if job.Priority < PriorityHigh || job.Priority > PriorityLow {
+		return "", fmt.Errorf("invalid job priority: %d", job.Priority)
+	}
+
+	member, err := json.Marshal(job)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal job: %w", err)
+	}
+
+	if delay > 0 {
+		err = q.client.ZAdd(ctx, q.delayedKey(queue), redis.Z{
+			Score:  float64(time.Now().Add(delay).UnixMilli()),
+			Member: member,
+		}).Err()
+	} else {
+		err = q.client.XAdd(ctx, &redis.XAddArgs{
+			Stream: q.streamKey(queue, job.Priority),
+			MaxLen: q.cfg.StreamMaxLen,
+			Approx: q.cfg.StreamMaxLen > 0,
+			Values: map[string]interface{}{"job": member},
+		}).Err()
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to enqueue job: %w", err)
+	}
+
+	return job.ID, nil
+}
+
+// DeadLetters 讀取 dead-letter stream 中最新的工作
+func (q *Queue) DeadLetters(ctx context.Context, queue string, count int64) ([]*Job, error) {
+	messages, err := q.client.XRevRangeN(ctx, q.deadKey(queue), "+", "-", count).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*Job, 0, len(messages))
+	for _, msg := range messages {
+		job, err := decodeJob(msg)
+		if err != nil {
+			continue
+		}
+		if reason, ok := msg.Values["error"].(string); ok {
+			job.LastError = reason
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// streamKey 佇列指定優先順序的 stream
+func (q *Queue) streamKey(queue string, priority Priority) string {
+	return q.keys.Key("queue", "{"+queue+"}", "p"+strconv.Itoa(int(priority)))
+}
+
+// streamKeys 佇列依優先順序排列的所有 stream
+func (q *Queue) streamKeys(queue string) []string {
+	keys := make([]string, 0, len(priorities))
+	for _, p := range priorities {
+		keys = append(keys, q.streamKey(queue, p))
+	}
+	return keys
+}
+
+// delayedKey 佇列的延遲工作 zset
+func (q *Queue) delayedKey(queue string) string {
+	return q.keys.Key("queue", "{"+queue+"}", "delayed")
+}
+
+// deadKey 佇列的 dead-letter stream
+func (q *Queue) deadKey(queue string) string {
+	return q.keys.Key("queue", "{"+queue+"}", "dead")
+}
+
+// handlerKey 處理函數的索引
+func handlerKey(queue, jobType string) string {
+	return queue + "/" + jobType
+}
+
+// decodeJob 解析 stream 訊息中的工作
+func decodeJob(msg redis.XMessage) (*Job, error) {
+	raw, ok := msg.Values["job"].(string)
+	if !ok {
+		return nil, fmt.Errorf("message %s has no job field", msg.ID)
+	}
+
+	var job Job
+	if err := json.Unmarshal([]byte(raw), &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal job %s: %w", msg.ID, err)
+	}
+	return &job, nil
+}
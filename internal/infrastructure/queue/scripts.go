@@ -0,0 +1,53 @@
+package queue
+
+import "github.com/redis/go-redis/v9"
+
+// 同一佇列的所有 key 以 hash tag 包住佇列名稱，Cluster 模式下位於同一個 slot
+
+// promoteScript 將到期的延遲工作移入對應優先順序的 stream
+// KEYS[1]: 延遲佇列（zset，score 為可執行時間），KEYS[2..]: 依優先順序排列的 stream
+// ARGV[1]: 目前時間（毫秒），ARGV[2]: 單次移動上限，ARGV[3]: stream 長度上限（0 不限制）
+var promoteScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+local maxlen = tonumber(ARGV[3])
+for _, member in ipairs(due) do
+	local job = cjson.decode(member)
+	local stream = KEYS[2 + math.min(math.max(tonumber(job.priority) or 1, 0), #KEYS - 2)]
+	if maxlen > 0 then
+		redis.call('XADD', stream, 'MAXLEN', '~', maxlen, '*', 'job', member)
+	else
+		redis.call('XADD', stream, '*', 'job', member)
+	end
+	redis.call('ZREM', KEYS[1], member)
+end
+return #due
+`)
+
+// ackScript 確認並刪除已完成的訊息
+// KEYS[1]: stream，ARGV[1]: consumer group，ARGV[2]: 訊息 ID
+var ackScript = redis.NewScript(`
+redis.call('XACK', KEYS[1], ARGV[1], ARGV[2])
+return redis.call('XDEL', KEYS[1], ARGV[2])
+`)
+
+// retryScript 確認訊息並以新的執行時間放回延遲佇列
+// KEYS[1]: stream，KEYS[2]: 延遲佇列
+// ARGV[1]: consumer group，ARGV[2]: 訊息 ID，ARGV[3]: 可執行時間（毫秒），ARGV[4]: 工作 JSON
+var retryScript = redis.NewScript(`
+redis.call('XACK', KEYS[1], ARGV[1], ARGV[2])
+redis.call('XDEL', KEYS[1], ARGV[2])
+return redis.call('ZADD', KEYS[2], ARGV[3], ARGV[4])
+`)
+
+// deadScript 確認訊息並移入 dead-letter stream
+// KEYS[1]: stream，KEYS[2]: dead-letter stream
+// ARGV[1]: consumer group，ARGV[2]: 訊息 ID，ARGV[3]: 工作 JSON，ARGV[4]: 錯誤訊息，ARGV[5]: stream 長度上限（0 不限制）
+var deadScript = redis.NewScript(`
+redis.call('XACK', KEYS[1], ARGV[1], ARGV[2])
+redis.call('XDEL', KEYS[1], ARGV[2])
+local maxlen = tonumber(ARGV[5])
+if maxlen > 0 then
+	return redis.call('XADD', KEYS[2], 'MAXLEN', '~', maxlen, '*', 'job', ARGV[3], 'error', ARGV[4])
+end
+return redis.call('XADD', KEYS[2], '*', 'job', ARGV[3], 'error', ARGV[4])
+`)
@@ -0,0 +1,401 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sync_drive_backend/internal/common/util"
+	"sync_drive_backend/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// delivery 領取到的訊息
+type delivery struct {
+	stream    string
+	msg       redis.XMessage
+	reclaimed bool // 由中斷的副本轉移而來，上一次執行視為失敗
+}
+
+// Start 建立 consumer group 並開始處理工作
+func (q *Queue) Start(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.cancel != nil {
+		return nil
+	}
+
+	for queue := range q.cfg.Concurrency {
+		for _, stream := range q.streamKeys(queue) {
+			err := q.client.XGroupCreateMkStream(ctx, stream, consumerGroup, "0").Err()
+			if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+				return fmt.Errorf("failed to create consumer group for %s: %w", stream, err)
+			}
+		}
+	}
+
+	fetchCtx, cancel := context.WithCancel(context.Background())
+	jobCtx, abort := context.WithCancel(context.Background())
+	q.cancel, q.abort = cancel, abort
+
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		q.promote(fetchCtx)
+	}()
+
+	for queue, concurrency := range q.cfg.Concurrency {
+		q.wg.Add(1)
+		go func(queue string, concurrency int) {
+			defer q.wg.Done()
+			q.fetch(fetchCtx, jobCtx, queue, concurrency)
+		}(queue, concurrency)
+	}
+
+	return nil
+}
+
+// Stop 停止領取新工作並等待處理中的工作完成
+// ctx 逾時時中止處理中的工作，未確認的工作會在 VisibilityTimeout 後由其他副本重新領取
+func (q *Queue) Stop(ctx context.Context) error {
+	q.mu.Lock()
+	cancel, abort := q.cancel, q.abort
+	q.cancel, q.abort = nil, nil
+	q.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	defer abort()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		q.jobs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// promote 定期將到期的延遲工作移入 stream
+func (q *Queue) promote(ctx context.Context) {
+	ticker := time.NewTicker(q.cfg.PromoteInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for queue := range q.cfg.Concurrency {
+			keys := append([]string{q.delayedKey(queue)}, q.streamKeys(queue)...)
+			for ctx.Err() == nil {
+				n, err := promoteScript.Run(ctx, q.client, keys, time.Now().UnixMilli(), 100, q.cfg.StreamMaxLen).Int()
+				if err != nil {
+					if ctx.Err() == nil {
+						logger.Error("Failed to promote delayed jobs", zap.String("queue", queue), zap.Error(err))
+					}
+					break
+				}
+				if n < 100 {
+					break
+				}
+			}
+		}
+	}
+}
+
+// fetch 單一佇列的領取迴圈，以 slots 限制同時處理的工作數量
+func (q *Queue) fetch(ctx, jobCtx context.Context, queue string, concurrency int) {
+	slots := make(chan struct{}, concurrency)
+	lastReclaim := time.Now()
+
+	for {
+		// 等待至少一個空位，並取得所有剩餘空位
+		select {
+		case <-ctx.Done():
+			return
+		case slots <- struct{}{}:
+		}
+		free := 1
+	fill:
+		for free < concurrency {
+			select {
+			case slots <- struct{}{}:
+				free++
+			default:
+				break fill
+			}
+		}
+
+		var deliveries []delivery
+		var err error
+		if time.Since(lastReclaim) >= q.cfg.ReclaimInterval {
+			lastReclaim = time.Now()
+			deliveries, err = q.reclaim(ctx, queue, free)
+		}
+		if err == nil && len(deliveries) == 0 {
+			deliveries, err = q.read(ctx, queue, free)
+		}
+		if err != nil && ctx.Err() == nil {
+			logger.Error("Failed to fetch jobs", zap.String("queue", queue), zap.Error(err))
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+
+		// 歸還未使用的空位
+		for i := len(deliveries); i < free; i++ {
+			<-slots
+		}
+
+		for _, d := range deliveries {
+			q.jobs.Add(1)
+			go func(d delivery) {
+				defer func() {
+					<-slots
+					q.jobs.Done()
+				}()
+				q.process(jobCtx, queue, d)
+			}(d)
+		}
+	}
+}
+
+// read 依優先順序讀取新工作，高優先順序沒有工作時才讀取較低的，全部為空時阻塞等待
+func (q *Queue) read(ctx context.Context, queue string, count int) ([]delivery, error) {
+	streams := q.streamKeys(queue)
+
+	for _, stream := range streams {
+		result, err := q.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    consumerGroup,
+			Consumer: q.consumer,
+			Streams:  []string{stream, ">"},
+			Count:    int64(count),
+			Block:    -1,
+		}).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+		if deliveries := toDeliveries(result); len(deliveries) > 0 {
+			return deliveries, nil
+		}
+	}
+
+	args := make([]string, 0, len(streams)*2)
+	args = append(args, streams...)
+	for range streams {
+		args = append(args, ">")
+	}
+	result, err := q.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    consumerGroup,
+		Consumer: q.consumer,
+		Streams:  args,
+		Count:    int64(count),
+		Block:    q.cfg.Block,
+	}).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	return toDeliveries(result), nil
+}
+
+// reclaim 以 XAUTOCLAIM 領取超過 VisibilityTimeout 未確認的工作
+func (q *Queue) reclaim(ctx context.Context, queue string, count int) ([]delivery, error) {
+	var deliveries []delivery
+
+	for _, stream := range q.streamKeys(queue) {
+		if len(deliveries) >= count {
+			break
+		}
+
+		messages, _, err := q.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   stream,
+			Group:    consumerGroup,
+			Consumer: q.consumer,
+			MinIdle:  q.cfg.VisibilityTimeout,
+			Start:    "0-0",
+			Count:    int64(count - len(deliveries)),
+		}).Result()
+		if err != nil {
+			return deliveries, err
+		}
+
+		for _, msg := range messages {
+			deliveries = append(deliveries, delivery{stream: stream, msg: msg, reclaimed: true})
+		}
+	}
+
+	if len(deliveries) > 0 {
+		logger.Warn("Reclaimed stalled jobs", zap.String("queue", queue), zap.Int("count", len(deliveries)))
+	}
+
+	return deliveries, nil
+}
+
+// process 處理單一工作並依結果確認、重試或移入 dead-letter
+func (q *Queue) process(ctx context.Context, queue string, d delivery) {
+	job, err := decodeJob(d.msg)
+	if err != nil {
+		q.dead(queue, d, nil, err)
+		return
+	}
+	if d.reclaimed {
+		job.Attempts++
+		job.LastError = "worker stalled or crashed"
+		if job.Attempts >= job.MaxAttempts {
+			q.dead(queue, d, job, errors.New(job.LastError))
+			return
+		}
+	}
+
+	q.mu.Lock()
+	handler, ok := q.handlers[handlerKey(queue, job.Type)]
+	q.mu.Unlock()
+	if !ok {
+		q.dead(queue, d, job, fmt.Errorf("no handler registered for job type %q", job.Type))
+		return
+	}
+
+	// 處理期間定期重設閒置時間，避免長時間工作被其他副本重新領取
+	stopHeartbeat := q.heartbeat(d)
+	err = q.run(ctx, handler, job)
+	stopHeartbeat()
+
+	if err == nil {
+		if err := ackScript.Run(context.Background(), q.client, []string{d.stream}, consumerGroup, d.msg.ID).Err(); err != nil {
+			logger.Error("Failed to ack job", zap.String("job_id", job.ID), zap.Error(err))
+		}
+		return
+	}
+
+	job.Attempts++
+	job.LastError = util.Truncate(err.Error(), 1024)
+
+	var permErr *permanentError
+	if errors.As(err, &permErr) || job.Attempts >= job.MaxAttempts {
+		q.dead(queue, d, job, err)
+		return
+	}
+
+	backoff := q.backoff(job.Attempts)
+	logger.Warn("Job failed, will retry",
+		zap.String("queue", queue),
+		zap.String("job_id", job.ID),
+		zap.String("type", job.Type),
+		zap.Int("attempts", job.Attempts),
+		zap.Duration("backoff", backoff),
+		zap.Error(err),
+	)
+
+	member, _ := json.Marshal(job)
+	readyAt := time.Now().Add(backoff).UnixMilli()
+	if err := retryScript.Run(context.Background(), q.client, []string{d.stream, q.delayedKey(queue)},
+		consumerGroup, d.msg.ID, readyAt, member).Err(); err != nil {
+		logger.Error("Failed to schedule job retry", zap.String("job_id", job.ID), zap.Error(err))
+	}
+}
+
+// run 執行處理函數，panic 視為失敗
+func (q *Queue) run(ctx context.Context, handler Handler, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, job)
+}
+
+// heartbeat 每隔 VisibilityTimeout/3 以 XCLAIM 重設訊息的閒置時間
+func (q *Queue) heartbeat(d delivery) func() {
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(q.cfg.VisibilityTimeout / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				err := q.client.XClaimJustID(context.Background(), &redis.XClaimArgs{
+					Stream:   d.stream,
+					Group:    consumerGroup,
+					Consumer: q.consumer,
+					Messages: []string{d.msg.ID},
+				}).Err()
+				if err != nil {
+					logger.Warn("Failed to extend job visibility", zap.String("message_id", d.msg.ID), zap.Error(err))
+				}
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// dead 將工作移入 dead-letter stream
+func (q *Queue) dead(queue string, d delivery, job *Job, cause error) {
+	member := d.msg.Values["job"]
+	if job != nil {
+		b, _ := json.Marshal(job)
+		member = string(b)
+	}
+	if member == nil {
+		member = ""
+	}
+
+	logger.Error("Job moved to dead-letter",
+		zap.String("queue", queue),
+		zap.String("message_id", d.msg.ID),
+		zap.Error(cause),
+	)
+
+	err := deadScript.Run(context.Background(), q.client, []string{d.stream, q.deadKey(queue)},
+		consumerGroup, d.msg.ID, member, util.Truncate(cause.Error(), 1024), q.cfg.StreamMaxLen).Err()
+	if err != nil {
+		logger.Error("Failed to move job to dead-letter", zap.String("message_id", d.msg.ID), zap.Error(err))
+	}
+}
+
+// backoff 計算第 n 次失敗後的退避時間
+func (q *Queue) backoff(attempts int) time.Duration {
+	d := q.cfg.RetryBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= q.cfg.MaxBackoff {
+			return q.cfg.MaxBackoff
+		}
+	}
+	return d
+}
+
+// toDeliveries 轉換 XREADGROUP 結果
+func toDeliveries(streams []redis.XStream) []delivery {
+	var deliveries []delivery
+	for _, s := range streams {
+		for _, msg := range s.Messages {
+			deliveries = append(deliveries, delivery{stream: s.Stream, msg: msg})
+		}
+	}
+	return deliveries
+}
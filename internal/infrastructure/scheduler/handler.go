@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"strconv"
+
+	"sync_drive_backend/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 排程管理端點
+type Handler struct {
+	scheduler *Scheduler
+}
+
+// NewHandler 創建排程管理端點
+func NewHandler(scheduler *Scheduler) *Handler {
+	return &Handler{scheduler: scheduler}
+}
+
+// RegisterRoutes 註冊路由（需由呼叫端套用管理員權限）
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/jobs", h.List)
+	rg.GET("/jobs/:name/runs", h.Runs)
+	rg.POST("/jobs/:name/trigger", h.Trigger)
+	rg.POST("/jobs/:name/pause", h.Pause)
+	rg.POST("/jobs/:name/resume", h.Resume)
+}
+
+// List 列出排程工作
+// GET /api/v1/admin/scheduler/jobs
+func (h *Handler) List(c *gin.Context) {
+	jobs, err := h.scheduler.Jobs(c.Request.Context())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, jobs)
+}
+
+// Runs 取得執行紀錄
+// GET /api/v1/admin/scheduler/jobs/:name/runs?limit=20
+func (h *Handler) Runs(c *gin.Context) {
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	runs, err := h.scheduler.Runs(c.Request.Context(), c.Param("name"), limit)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, runs)
+}
+
+// Trigger 立即執行
+// POST /api/v1/admin/scheduler/jobs/:name/trigger
+func (h *Handler) Trigger(c *gin.Context) {
+	runID, err := h.scheduler.Trigger(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, gin.H{"run_id": runID})
+}
+
+// Pause 暫停
+// POST /api/v1/admin/scheduler/jobs/:name/pause
+func (h *Handler) Pause(c *gin.Context) {
+	if err := h.scheduler.Pause(c.Request.Context(), c.Param("name")); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, nil)
+}
+
+// Resume 恢復
+// POST /api/v1/admin/scheduler/jobs/:name/resume
+func (h *Handler) Resume(c *gin.Context) {
+	if err := h.scheduler.Resume(c.Request.Context(), c.Param("name")); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.Success(c, nil)
+}
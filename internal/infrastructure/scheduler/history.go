@@ -0,0 +1,46 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// Trigger 觸發方式
+type Trigger string
+
+const (
+	TriggerSchedule Trigger = "schedule" // 排程觸發
+	TriggerManual   Trigger = "manual"   // 管理端點手動觸發
+)
+
+// RunStatus 執行狀態
+type RunStatus string
+
+const (
+	RunStatusRunning   RunStatus = "running"
+	RunStatusSucceeded RunStatus = "succeeded"
+	RunStatusFailed    RunStatus = "failed"
+)
+
+// Run 執行紀錄
+type Run struct {
+	ID          string    `json:"id"`
+	Job         string    `json:"job"`
+	Trigger     Trigger   `json:"trigger"`
+	Status      RunStatus `json:"status"`
+	Replica     string    `json:"replica"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// RunHistory 執行紀錄儲存
+type RunHistory interface {
+	// Start 寫入開始紀錄，run.ID 由排程器產生（ObjectID 十六進位字串）
+	Start(ctx context.Context, run *Run) error
+	// Finish 更新結束時間、狀態與錯誤
+	Finish(ctx context.Context, run *Run) error
+	// Recent 依開始時間倒序取得最近的紀錄
+	Recent(ctx context.Context, job string, limit int64) ([]*Run, error)
+}
@@ -0,0 +1,425 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	redisinfra "sync_drive_backend/internal/infrastructure/persistence/redis"
+	apperrors "sync_drive_backend/pkg/errors"
+	"sync_drive_backend/pkg/logger"
+	"sync_drive_backend/pkg/tools"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/robfig/cron/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// JobFunc 排程工作內容，ctx 在逾時、鎖遺失或停止時取消
+type JobFunc func(ctx context.Context) error
+
+// Job 排程工作定義
+type Job struct {
+	Name     string        // 唯一名稱，同時作為鎖與執行紀錄的識別
+	Spec     string        // cron 表達式（分 時 日 月 週），或 @daily、@every 1h 等描述
+	TimeZone string        // IANA 時區（例如：Asia/Taipei），空值使用排程器預設時區
+	Timeout  time.Duration // 單次執行逾時，0 使用排程器預設值
+	Run      JobFunc
+}
+
+// JobStatus 排程工作狀態
+type JobStatus struct {
+	Name     string    `json:"name"`
+	Spec     string    `json:"spec"`
+	TimeZone string    `json:"time_zone"`
+	Paused   bool      `json:"paused"`
+	NextRun  time.Time `json:"next_run"`
+	LastRun  *Run      `json:"last_run,omitempty"`
+}
+
+// Config 排程器配置
+type Config struct {
+	Location       *time.Location // 預設時區
+	LockTTL        time.Duration  // 執行鎖與 tick 認領的存活時間
+	DefaultTimeout time.Duration  // 預設執行逾時
+}
+
+// registered 已註冊的工作
+type registered struct {
+	job     Job
+	entryID cron.EntryID
+}
+
+// Scheduler 分散式 cron 排程器
+// 每個副本都運行相同的排程，觸發時以 Redis 認領該次 tick 並取得執行鎖，確保同一次 tick 只在一個副本執行，
+// 且前一次執行尚未結束時不會重疊；暫停狀態存放於 Redis，所有副本共用
+type Scheduler struct {
+	cron    *cron.Cron
+	client  redis.UniversalClient
+	keys    *redisinfra.KeyBuilder
+	locker  *redisinfra.Locker
+	history RunHistory
+	cfg     *Config
+	replica string
+
+	mu     sync.RWMutex
+	jobs   map[string]*registered
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup // 手動觸發的執行
+}
+
+// New 創建排程器
+func New(client redis.UniversalClient, keys *redisinfra.KeyBuilder, locker *redisinfra.Locker, history RunHistory, cfg *Config) *Scheduler {
+	hostname, _ := os.Hostname()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Scheduler{
+		cron:    cron.New(cron.WithLocation(cfg.Location)),
+		client:  client,
+		keys:    keys,
+		locker:  locker,
+		history: history,
+		cfg:     cfg,
+		replica: hostname + "-" + tools.GenerateUUID()[:8],
+		jobs:    make(map[string]*registered),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Register 註冊排程工作
+//
+//	scheduler.Register(scheduler.Job{
+//		Name:     "fleet.daily-report",
+//		Spec:     "0 8 * * *",
+//		TimeZone: "Asia/Taipei",
+//		Run:      reportService.SendDailyReports,
+//	})
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return errors.New("scheduled job requires name and run function")
+	}
+
+	spec := job.Spec
+	if job.TimeZone != "" {
+		if _, err := time.LoadLocation(job.TimeZone); err != nil {
+			return fmt.Errorf("invalid time zone of job %s: %w", job.Name, err)
+		}
+		spec = "CRON_TZ=" + job.TimeZone + " " + spec
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("scheduled job %q already registered", job.Name)
+	}
+
+	entryID, err := s.cron.AddFunc(spec, func() { s.tick(job.Name) })
+	if err != nil {
+		return fmt.Errorf("invalid cron spec of job %s: %w", job.Name, err)
+	}
+	s.jobs[job.Name] = &registered{job: job, entryID: entryID}
+
+	return nil
+}
+
+// Start 啟動排程
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Stop 停止排程並等待執行中的工作完成，ctx 逾時時取消執行中的工作
+func (s *Scheduler) Stop(ctx context.Context) error {
+	cronCtx := s.cron.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		<-cronCtx.Done()
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+// Jobs 列出所有排程工作與最近一次執行紀錄
+func (s *Scheduler) Jobs(ctx context.Context) ([]*JobStatus, error) {
+	paused, err := s.client.HGetAll(ctx, s.pausedKey()).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	statuses := make([]*JobStatus, 0, len(s.jobs))
+	for name, r := range s.jobs {
+		_, isPaused := paused[name]
+		statuses = append(statuses, &JobStatus{
+			Name:     name,
+			Spec:     r.job.Spec,
+			TimeZone: s.timeZone(&r.job),
+			Paused:   isPaused,
+			NextRun:  s.cron.Entry(r.entryID).Next,
+		})
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+
+	for _, status := range statuses {
+		runs, err := s.history.Recent(ctx, status.Name, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(runs) > 0 {
+			status.LastRun = runs[0]
+		}
+	}
+
+	return statuses, nil
+}
+
+// Runs 取得工作最近的執行紀錄
+func (s *Scheduler) Runs(ctx context.Context, name string, limit int64) ([]*Run, error) {
+	if _, err := s.lookup(name); err != nil {
+		return nil, err
+	}
+	return s.history.Recent(ctx, name, limit)
+}
+
+// Trigger 立即在本副本執行工作（不受暫停影響），返回執行紀錄 ID
+// 工作正在其他副本執行時返回 ErrAlreadyExists
+func (s *Scheduler) Trigger(ctx context.Context, name string) (string, error) {
+	job, err := s.lookup(name)
+	if err != nil {
+		return "", err
+	}
+
+	lock, err := s.locker.TryLock(ctx, s.lockName(name), s.cfg.LockTTL)
+	if errors.Is(err, redisinfra.ErrLockNotAcquired) {
+		return "", apperrors.New(apperrors.ErrAlreadyExists, "job is already running")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	run := s.newRun(name, TriggerManual, time.Now())
+	s.startRun(ctx, run)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.execute(&job, lock, run)
+	}()
+
+	return run.ID, nil
+}
+
+// Pause 暫停工作（所有副本）
+func (s *Scheduler) Pause(ctx context.Context, name string) error {
+	if _, err := s.lookup(name); err != nil {
+		return err
+	}
+	return s.client.HSet(ctx, s.pausedKey(), name, time.Now().Unix()).Err()
+}
+
+// Resume 恢復工作（所有副本）
+func (s *Scheduler) Resume(ctx context.Context, name string) error {
+	if _, err := s.lookup(name); err != nil {
+		return err
+	}
+	return s.client.HDel(ctx, s.pausedKey(), name).Err()
+}
+
+// tick 排程觸發：檢查暫停、認領 tick、取得執行鎖後執行
+func (s *Scheduler) tick(name string) {
+	job, err := s.lookup(name)
+	if err != nil {
+		return
+	}
+	scheduledAt := s.scheduledAt(name)
+
+	ctx, cancel := context.WithTimeout(s.ctx, 5*time.Second)
+	defer cancel()
+
+	paused, err := s.client.HExists(ctx, s.pausedKey(), name).Result()
+	if err != nil {
+		logger.Error("Failed to check scheduled job state", zap.String("job", name), zap.Error(err))
+		return
+	}
+	if paused {
+		return
+	}
+
+	claimed, err := s.client.SetNX(ctx, s.tickKey(name, scheduledAt), s.replica, s.cfg.LockTTL).Result()
+	if err != nil {
+		logger.Error("Failed to claim scheduled tick", zap.String("job", name), zap.Error(err))
+		return
+	}
+	if !claimed {
+		return // 其他副本已執行此次 tick
+	}
+
+	lock, err := s.locker.TryLock(ctx, s.lockName(name), s.cfg.LockTTL)
+	if errors.Is(err, redisinfra.ErrLockNotAcquired) {
+		logger.Warn("Scheduled job skipped, previous run still in progress", zap.String("job", name))
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to acquire scheduled job lock", zap.String("job", name), zap.Error(err))
+		return
+	}
+
+	run := s.newRun(name, TriggerSchedule, scheduledAt)
+	s.startRun(ctx, run)
+
+	// 在 cron 的 goroutine 中執行，Stop 時由 cron 等待完成
+	s.execute(&job, lock, run)
+}
+
+// execute 執行工作並記錄結果
+func (s *Scheduler) execute(job *Job, lock *redisinfra.Lock, run *Run) {
+	defer func() {
+		if err := lock.Unlock(context.Background()); err != nil && !errors.Is(err, redisinfra.ErrLockNotHeld) {
+			logger.Warn("Failed to release scheduled job lock", zap.String("job", job.Name), zap.Error(err))
+		}
+	}()
+
+	timeout := job.Timeout
+	if timeout <= 0 {
+		timeout = s.cfg.DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(s.ctx, timeout)
+	defer cancel()
+
+	// 鎖遺失時其他副本可能已開始執行，立即取消
+	go func() {
+		select {
+		case <-lock.Lost():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err := runJob(ctx, job.Run)
+
+	run.FinishedAt = time.Now()
+	run.Status = RunStatusSucceeded
+	if err != nil {
+		run.Status = RunStatusFailed
+		run.Error = err.Error()
+		logger.Error("Scheduled job failed",
+			zap.String("job", job.Name),
+			zap.String("trigger", string(run.Trigger)),
+			zap.Duration("duration", run.FinishedAt.Sub(run.StartedAt)),
+			zap.Error(err),
+		)
+	} else {
+		logger.Info("Scheduled job finished",
+			zap.String("job", job.Name),
+			zap.String("trigger", string(run.Trigger)),
+			zap.Duration("duration", run.FinishedAt.Sub(run.StartedAt)),
+		)
+	}
+
+	finishCtx, finishCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer finishCancel()
+	if err := s.history.Finish(finishCtx, run); err != nil {
+		logger.Error("Failed to record scheduled job result", zap.String("job", job.Name), zap.Error(err))
+	}
+}
+
+// runJob 執行工作內容，panic 視為失敗
+func runJob(ctx context.Context, fn JobFunc) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return fn(ctx)
+}
+
+// newRun 建立執行紀錄，ID 在此產生，寫入開始紀錄失敗時仍可返回與追蹤
+func (s *Scheduler) newRun(name string, trigger Trigger, scheduledAt time.Time) *Run {
+	return &Run{
+		ID:          primitive.NewObjectID().Hex(),
+		Job:         name,
+		Trigger:     trigger,
+		Status:      RunStatusRunning,
+		Replica:     s.replica,
+		ScheduledAt: scheduledAt,
+		StartedAt:   time.Now(),
+	}
+}
+
+// startRun 寫入開始紀錄，失敗時只記錄日誌不影響執行
+func (s *Scheduler) startRun(ctx context.Context, run *Run) {
+	if err := s.history.Start(ctx, run); err != nil {
+		logger.Error("Failed to record scheduled job start", zap.String("job", run.Job), zap.Error(err))
+	}
+}
+
+// scheduledAt 本次 tick 的排定時間
+// 取自 cron 排程計算的觸發時間（Entry.Prev），所有副本對同一次 tick 得到相同的值，不受觸發延遲與時鐘誤差影響
+func (s *Scheduler) scheduledAt(name string) time.Time {
+	s.mu.RLock()
+	r, ok := s.jobs[name]
+	s.mu.RUnlock()
+
+	if ok {
+		if prev := s.cron.Entry(r.entryID).Prev; !prev.IsZero() {
+			return prev
+		}
+	}
+	return time.Now().Truncate(time.Second)
+}
+
+// lookup 取得已註冊的工作
+func (s *Scheduler) lookup(name string) (Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.jobs[name]
+	if !ok {
+		return Job{}, apperrors.New(apperrors.ErrNotFound, "scheduled job not found")
+	}
+	return r.job, nil
+}
+
+// timeZone 工作實際使用的時區
+func (s *Scheduler) timeZone(job *Job) string {
+	if job.TimeZone != "" {
+		return job.TimeZone
+	}
+	return s.cfg.Location.String()
+}
+
+// lockName 執行鎖名稱
+func (s *Scheduler) lockName(name string) string {
+	return "scheduler:" + name
+}
+
+// pausedKey 暫停狀態（hash：工作名稱 → 暫停時間）
+func (s *Scheduler) pausedKey() string {
+	return s.keys.Key("scheduler", "paused")
+}
+
+// tickKey 單次 tick 的認領 key
+func (s *Scheduler) tickKey(name string, scheduledAt time.Time) string {
+	return s.keys.Key("scheduler", "tick", name, strconv.FormatInt(scheduledAt.Unix(), 10))
+}
@@ -1,40 +1,75 @@
 package webserver
 
 import (
+	"fmt"
+
+	"sync_drive_backend/internal/common/consts"
+	"sync_drive_backend/internal/common/idempotency"
+	"sync_drive_backend/internal/common/middleware/auth"
 	"sync_drive_backend/internal/common/middleware/logging"
 	"sync_drive_backend/internal/common/middleware/request"
+	authhttp "sync_drive_backend/internal/core/auth/interface/http"
+	"sync_drive_backend/internal/infrastructure/scheduler"
 	"sync_drive_backend/internal/infrastructure/webserver/health"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Config Router 配置
+type Config struct {
+	// TrustedProxies 信任的反向代理 CIDR（例如：負載平衡器所在網段）
+	// 只有來自這些代理的請求才會採用 RemoteIPHeaders 作為客戶端 IP，空值表示不信任任何代理
+	TrustedProxies  []string
+	RemoteIPHeaders []string // 代理傳遞客戶端 IP 的 header，依序檢查
+	TrustedPlatform string   // 雲端平台提供的客戶端 IP header（例如：CF-Connecting-IP），優先於 RemoteIPHeaders
+	Idempotency     *request.IdempotencyConfig
+}
+
 // SetupRouter 設定路由
-func SetupRouter() *gin.Engine {
+func SetupRouter(cfg *Config, rateLimiter *request.DistributedRateLimiter, idempotencyStore idempotency.Store, tokenVerifier auth.TokenVerifier, permissionChecker auth.PermissionChecker, authController *authhttp.Controller, schedulerHandler *scheduler.Handler) (*gin.Engine, error) {
 	// 創建 Gin Engine
 	router := gin.New()
 
+	// 設定信任的代理，避免偽造 X-Forwarded-For 繞過以 IP 為單位的限流
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	if len(cfg.RemoteIPHeaders) > 0 {
+		router.RemoteIPHeaders = cfg.RemoteIPHeaders
+	}
+	router.TrustedPlatform = cfg.TrustedPlatform
+
 	// 全局中介層
 	router.Use(gin.Recovery())           // 恢復 panic
 	router.Use(request.RequestID())      // Request ID 追蹤
 	router.Use(logging.Logger())         // 請求日誌記錄
+	router.Use(auth.OptionalJWTAuth(tokenVerifier)) // 辨識用戶身分（不強制驗證），限流依用戶與角色區分額度
+	router.Use(rateLimiter.RateLimit())  // 分散式限流
+	router.Use(request.Idempotency(idempotencyStore, cfg.Idempotency)) // Idempotency-Key 重播
 
 	// 健康檢查端點（不需要認證）
 	healthHandler := health.NewHandler()
 	router.GET("/health", healthHandler.Check)
 
+	// 公開的 Token 驗證金鑰（不需要認證）
+	authController.RegisterWellKnown(router)
+
+	authz := auth.NewAuthorizer(permissionChecker)
+
 	// API 路由群組
 	api := router.Group("/api/v1")
 	{
+		// 身份驗證
+		authController.RegisterRoutes(api.Group("/auth"), auth.JWTAuth(tokenVerifier), authz)
+
 		// TODO: 註冊業務路由
-		// 例如：
-		// auth := api.Group("/auth")
-		// {
-		//     auth.POST("/login", authController.Login)
-		//     auth.POST("/register", authController.Register)
-		// }
-
-		_ = api // 避免未使用變數錯誤
+
+		// 管理端點
+		admin := api.Group("/admin", auth.JWTAuth(tokenVerifier))
+		{
+			schedulerHandler.RegisterRoutes(admin.Group("/scheduler", authz.RequirePermission(consts.PermissionSchedulerManage)))
+		}
 	}
 
-	return router
+	return router, nil
 }
@@ -7,8 +7,16 @@ const (
 
 // Application errors (1-999)
 const (
-	ErrInternalError = 1
-	ErrUnauthorized  = 2
+	ErrInternalError       = 1
+	ErrUnauthorized        = 2
+	ErrVersionConflict     = 3  // 樂觀鎖版本衝突
+	ErrNotFound            = 4  // 資源不存在
+	ErrAlreadyExists       = 5  // 資源已存在（唯一鍵衝突）
+	ErrTooManyRequests     = 6  // 請求過於頻繁
+	ErrRequestInProgress   = 7  // 相同 Idempotency-Key 的請求仍在處理中
+	ErrIdempotencyKeyReuse = 8  // Idempotency-Key 已用於不同的請求內容
+	ErrInvalidParams       = 9  // 請求參數錯誤
+	ErrForbidden           = 10 // 已驗證身分但無權限
 )
 
 // Database errors (1000-1099)
@@ -1,6 +1,9 @@
 package errors
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // AppError 自定義應用程式錯誤類型
 type AppError struct {
@@ -17,6 +20,11 @@ func (e *AppError) Error() string {
 	return fmt.Sprintf("[%d] %s", e.Code, e.Message)
 }
 
+// Unwrap 返回原始錯誤，支援 errors.Is / errors.As
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
 // New 建立新的 AppError
 func New(code int, message string) *AppError {
 	return &AppError{
@@ -33,3 +41,12 @@ func Wrap(code int, message string, err error) *AppError {
 		Err:     err,
 	}
 }
+
+// IsCode 檢查錯誤鏈中是否包含指定錯誤碼的 AppError
+func IsCode(err error, code int) bool {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code == code
+	}
+	return false
+}
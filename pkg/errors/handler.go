@@ -1,6 +1,7 @@
 package errors
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -15,7 +16,8 @@ type Response struct {
 
 // HandleError 處理錯誤並回應
 func HandleError(c *gin.Context, err error) {
-	if appErr, ok := err.(*AppError); ok {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
 		// 自定義錯誤
 		c.JSON(getHTTPStatus(appErr.Code), Response{
 			Code:    appErr.Code,
@@ -40,6 +42,18 @@ func Success(c *gin.Context, data interface{}) {
 	})
 }
 
+// httpStatusMapping 需要細分 HTTP 狀態碼的錯誤碼，優先於下方的區間規則
+var httpStatusMapping = map[int]int{
+	ErrVersionConflict:     http.StatusConflict,
+	ErrNotFound:            http.StatusNotFound,
+	ErrAlreadyExists:       http.StatusConflict,
+	ErrTooManyRequests:     http.StatusTooManyRequests,
+	ErrRequestInProgress:   http.StatusConflict,
+	ErrIdempotencyKeyReuse: http.StatusUnprocessableEntity,
+	ErrUnauthorized:        http.StatusUnauthorized,
+	ErrForbidden:           http.StatusForbidden,
+}
+
 //	 Mapping rules:
 //		code 0: Success → 200 OK
 //		code 1-999: Application errors → 400 Bad Request (client errors)
@@ -55,6 +69,11 @@ func getHTTPStatus(code int) int {
 		return http.StatusOK
 	}
 
+	// 指定對應
+	if status, ok := httpStatusMapping[code]; ok {
+		return status
+	}
+
 	// Application errors (1-999)
 	if code >= 1 && code <= 999 {
 		// Default: client error
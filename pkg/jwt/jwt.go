@@ -1,42 +1,153 @@
 package jwt
 
 import (
+	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// Token 類型
+const (
+	TokenTypeAccess  = "access"  // 存取 API 用
+	TokenTypeRefresh = "refresh" // 僅用於換發新的 Token
+)
+
+// ErrTokenType Token 類型不符（例如以 Refresh Token 存取 API）
+var ErrTokenType = errors.New("unexpected token type")
+
 // Claims JWT 自定義聲明
 type Claims struct {
-	UserID   string `json:"userId"`
-	Username string `json:"username"`
-	RoleId   string `json:"roleId"`
+	UserID    string `json:"userId"`
+	Username  string `json:"username"`
+	RoleId    string `json:"roleId"`
+	TokenType string `json:"typ,omitempty"` // 未設定視為 Access Token
+	SessionID string `json:"sid,omitempty"` // 所屬的登入 Session（同一次登入輪替出的 Token 共用）
 	jwt.RegisteredClaims
 }
 
-// Generate 生成 JWT Token
-func Generate(userID, username, roleID, secret string, expireHours int) (string, error) {
-	claims := Claims{
+// TokenPair Access Token 與 Refresh Token
+type TokenPair struct {
+	AccessToken      string
+	AccessID         string // Access Token 的 jti，撤銷 Session 時加入黑名單
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshID        string // Refresh Token 的 jti，用於輪替時比對
+	RefreshExpiresAt time.Time
+}
+
+// Generate 生成 Access Token（不屬於登入 Session，例如設備或服務帳號）
+func (ks *KeySet) Generate(userID, username, roleID string, expireHours int) (string, error) {
+	claims := ks.newClaims(userID, username, roleID, time.Now().Add(time.Duration(expireHours)*time.Hour))
+	claims.TokenType = TokenTypeAccess
+	claims.ID = uuid.NewString()
+	return ks.sign(claims)
+}
+
+// GeneratePair 生成屬於 sessionID 的 Access Token 與 Refresh Token
+func (ks *KeySet) GeneratePair(userID, username, roleID, sessionID string, accessExpireHours, refreshExpireHours int) (*TokenPair, error) {
+	now := time.Now()
+	pair := &TokenPair{
+		AccessID:         uuid.NewString(),
+		AccessExpiresAt:  now.Add(time.Duration(accessExpireHours) * time.Hour),
+		RefreshID:        uuid.NewString(),
+		RefreshExpiresAt: now.Add(time.Duration(refreshExpireHours) * time.Hour),
+	}
+
+	access := ks.newClaims(userID, username, roleID, pair.AccessExpiresAt)
+	access.TokenType = TokenTypeAccess
+	access.SessionID = sessionID
+	access.ID = pair.AccessID
+
+	refresh := ks.newClaims(userID, username, roleID, pair.RefreshExpiresAt)
+	refresh.TokenType = TokenTypeRefresh
+	refresh.SessionID = sessionID
+	refresh.ID = pair.RefreshID
+
+	var err error
+	if pair.AccessToken, err = ks.sign(access); err != nil {
+		return nil, err
+	}
+	if pair.RefreshToken, err = ks.sign(refresh); err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// Parse 解析 Access Token
+func (ks *KeySet) Parse(tokenString string) (*Claims, error) {
+	claims, err := ks.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != "" && claims.TokenType != TokenTypeAccess {
+		return nil, ErrTokenType
+	}
+	return claims, nil
+}
+
+// ParseRefresh 解析 Refresh Token
+func (ks *KeySet) ParseRefresh(tokenString string) (*Claims, error) {
+	claims, err := ks.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != TokenTypeRefresh || claims.SessionID == "" || claims.ID == "" {
+		return nil, ErrTokenType
+	}
+	return claims, nil
+}
+
+// Verify 驗證 Access Token 是否有效
+func (ks *KeySet) Verify(tokenString string) bool {
+	_, err := ks.Parse(tokenString)
+	return err == nil
+}
+
+// newClaims 建立共用的聲明
+func (ks *KeySet) newClaims(userID, username, roleID string, expiresAt time.Time) *Claims {
+	now := time.Now()
+	claims := &Claims{
 		UserID:   userID,
 		Username: username,
 		RoleId:   roleID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(expireHours) * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			Issuer:    ks.issuer,
+			Subject:   userID,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
+	if ks.audience != "" {
+		claims.Audience = jwt.ClaimStrings{ks.audience}
+	}
+	return claims
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+// sign 以簽發金鑰簽署 Token，header 帶 kid 供驗證端選擇金鑰
+func (ks *KeySet) sign(claims *Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signing.method, claims)
+	token.Header["kid"] = ks.signing.ID
+	return token.SignedString(ks.signing.signKey)
 }
 
-// Parse 解析 JWT Token
-func Parse(tokenString, secret string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
-	})
+// parse 驗證演算法、簽章、時效、簽發者與受眾
+func (ks *KeySet) parse(tokenString string) (*Claims, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(ks.methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if ks.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(ks.issuer))
+	}
+	if ks.audience != "" {
+		opts = append(opts, jwt.WithAudience(ks.audience))
+	}
 
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, ks.lookup, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -47,9 +158,3 @@ func Parse(tokenString, secret string) (*Claims, error) {
 
 	return nil, jwt.ErrTokenInvalidClaims
 }
-
-// Verify 驗證 JWT Token 是否有效
-func Verify(tokenString, secret string) bool {
-	_, err := Parse(tokenString, secret)
-	return err == nil
-}
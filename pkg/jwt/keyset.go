@@ -0,0 +1,216 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 支援的簽章演算法
+const (
+	AlgHS256 = "HS256" // 共用密鑰，僅適用單一服務（本地與開發環境）
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+	AlgEdDSA = "EdDSA"
+)
+
+// KeyConfig 金鑰配置
+type KeyConfig struct {
+	ID             string // kid
+	Algorithm      string // RS256, ES256, EdDSA, HS256
+	PrivateKeyFile string // PEM 私鑰，僅驗證用（已退役或尚未啟用）的金鑰可留空
+	PublicKeyFile  string // PEM 公鑰，留空時由私鑰推導
+	Secret         string // HS256 共用密鑰
+}
+
+// KeySetConfig 金鑰組配置
+type KeySetConfig struct {
+	Keys         []KeyConfig
+	SigningKeyID string // 簽發新 Token 使用的金鑰，其餘金鑰僅用於驗證
+	Issuer       string // iss，簽發時寫入並於驗證時比對
+	Audience     string // aud，簽發時寫入並於驗證時比對
+}
+
+// Key 簽章金鑰
+type Key struct {
+	ID        string
+	Algorithm string
+	method    jwt.SigningMethod
+	signKey   interface{} // 私鑰（HS256 為密鑰），nil 表示僅驗證
+	verifyKey interface{} // 公鑰（HS256 為密鑰）
+}
+
+// CanSign 是否可用於簽發
+func (k *Key) CanSign() bool {
+	return k.signKey != nil
+}
+
+// KeySet 金鑰組
+// 輪替流程：新增金鑰（僅公鑰或私鑰）並部署 → 所有節點都能驗證後將 SigningKeyID 切換為新金鑰 →
+// 舊 Token 全部過期後移除舊金鑰
+type KeySet struct {
+	signing  *Key
+	keys     map[string]*Key
+	methods  []string // 允許的演算法，拒絕其他演算法（例如 none 或以公鑰當作 HS256 密鑰）
+	issuer   string
+	audience string
+}
+
+// NewKeySet 載入金鑰組
+func NewKeySet(cfg *KeySetConfig) (*KeySet, error) {
+	if len(cfg.Keys) == 0 {
+		return nil, fmt.Errorf("jwt: no keys configured")
+	}
+
+	ks := &KeySet{
+		keys:     make(map[string]*Key, len(cfg.Keys)),
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+	}
+
+	seen := make(map[string]bool)
+	for i := range cfg.Keys {
+		key, err := loadKey(&cfg.Keys[i])
+		if err != nil {
+			return nil, fmt.Errorf("jwt: failed to load key %q: %w", cfg.Keys[i].ID, err)
+		}
+		if _, ok := ks.keys[key.ID]; ok {
+			return nil, fmt.Errorf("jwt: duplicate key id %q", key.ID)
+		}
+		ks.keys[key.ID] = key
+
+		if !seen[key.Algorithm] {
+			seen[key.Algorithm] = true
+			ks.methods = append(ks.methods, key.Algorithm)
+		}
+	}
+
+	signing, ok := ks.keys[cfg.SigningKeyID]
+	if !ok {
+		return nil, fmt.Errorf("jwt: signing key %q not found", cfg.SigningKeyID)
+	}
+	if !signing.CanSign() {
+		return nil, fmt.Errorf("jwt: signing key %q has no private key", cfg.SigningKeyID)
+	}
+	ks.signing = signing
+
+	return ks, nil
+}
+
+// lookup 依 Token header 選擇驗證金鑰，演算法必須與金鑰一致
+func (ks *KeySet) lookup(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key := ks.signing
+	if kid != "" {
+		var ok bool
+		if key, ok = ks.keys[kid]; !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+	}
+
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method %q for key %q", token.Method.Alg(), key.ID)
+	}
+	return key.verifyKey, nil
+}
+
+// loadKey 載入單一金鑰
+func loadKey(cfg *KeyConfig) (*Key, error) {
+	if cfg.ID == "" {
+		return nil, fmt.Errorf("key id is required")
+	}
+
+	key := &Key{
+		ID:        cfg.ID,
+		Algorithm: cfg.Algorithm,
+		method:    jwt.GetSigningMethod(cfg.Algorithm),
+	}
+
+	switch cfg.Algorithm {
+	case AlgHS256:
+		if cfg.Secret == "" {
+			return nil, fmt.Errorf("secret is required for %s", AlgHS256)
+		}
+		key.signKey = []byte(cfg.Secret)
+		key.verifyKey = []byte(cfg.Secret)
+		return key, nil
+	case AlgRS256, AlgES256, AlgEdDSA:
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", cfg.Algorithm)
+	}
+
+	if cfg.PrivateKeyFile != "" {
+		pem, err := os.ReadFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		signer, err := parsePrivateKey(cfg.Algorithm, pem)
+		if err != nil {
+			return nil, err
+		}
+		key.signKey = signer
+		key.verifyKey = signer.Public()
+	}
+
+	if cfg.PublicKeyFile != "" {
+		pem, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if key.verifyKey, err = parsePublicKey(cfg.Algorithm, pem); err != nil {
+			return nil, err
+		}
+	}
+
+	if key.verifyKey == nil {
+		return nil, fmt.Errorf("privateKeyFile or publicKeyFile is required")
+	}
+	return key, nil
+}
+
+// parsePrivateKey 解析 PEM 私鑰並確認與演算法相符
+func parsePrivateKey(alg string, pem []byte) (crypto.Signer, error) {
+	switch alg {
+	case AlgRS256:
+		return jwt.ParseRSAPrivateKeyFromPEM(pem)
+	case AlgES256:
+		key, err := jwt.ParseECPrivateKeyFromPEM(pem)
+		if err != nil {
+			return nil, err
+		}
+		if key.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("%s requires a P-256 key", AlgES256)
+		}
+		return key, nil
+	default:
+		key, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+		if err != nil {
+			return nil, err
+		}
+		return key.(ed25519.PrivateKey), nil
+	}
+}
+
+// parsePublicKey 解析 PEM 公鑰並確認與演算法相符
+func parsePublicKey(alg string, pem []byte) (crypto.PublicKey, error) {
+	switch alg {
+	case AlgRS256:
+		return jwt.ParseRSAPublicKeyFromPEM(pem)
+	case AlgES256:
+		key, err := jwt.ParseECPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, err
+		}
+		if key.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("%s requires a P-256 key", AlgES256)
+		}
+		return key, nil
+	default:
+		return jwt.ParseEdPublicKeyFromPEM(pem)
+	}
+}